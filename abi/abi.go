@@ -0,0 +1,335 @@
+// Package abi describes the calls, results and state of a contract in JSON,
+// and encodes them with borsh like the javy js_sdk does. It is unrelated to
+// the host ABI of the execution package.
+//
+// An ABI looks like:
+//
+//	{
+//	  "types": {
+//	    "Counters": {"fields": [{"name": "users", "type": {"vec": "address"}}]}
+//	  },
+//	  "calls": [
+//	    {"name": "increment", "variant": 0, "args": [{"name": "amount", "type": "u64"}]},
+//	    {"name": "get", "variant": 1, "args": [], "result": "u64"}
+//	  ],
+//	  "state": "Counters"
+//	}
+//
+// A type is either the name of a primitive or of an entry of "types", or an
+// object: {"vec": T}, {"option": T}, {"array": T, "length": N} or
+// {"fields": [...]} for an inline struct. The primitives are bool, u8, u16,
+// u32, u64, i8, i16, i32, i64, f32, f64, string, bytes (a vec of u8) and
+// address (33 bytes).
+//
+// Payloads are the variant of the call as one byte followed by its args.
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownType   = errors.New("unknown type")
+	ErrInvalidType   = errors.New("invalid type")
+	ErrRecursiveType = errors.New("type contains itself")
+	ErrDuplicateCall = errors.New("duplicate call")
+	ErrUnknownCall   = errors.New("unknown call")
+	ErrNoResult      = errors.New("call has no result")
+	ErrNoState       = errors.New("abi has no state")
+)
+
+const (
+	Bool    = "bool"
+	U8      = "u8"
+	U16     = "u16"
+	U32     = "u32"
+	U64     = "u64"
+	I8      = "i8"
+	I16     = "i16"
+	I32     = "i32"
+	I64     = "i64"
+	F32     = "f32"
+	F64     = "f64"
+	String  = "string"
+	Bytes   = "bytes"
+	Address = "address"
+)
+
+func isPrimitive(name string) bool {
+	switch name {
+	case Bool, U8, U16, U32, U64, I8, I16, I32, I64, F32, F64, String, Bytes, Address:
+		return true
+	default:
+		return false
+	}
+}
+
+// Type is exactly one of a [Name], a [Vec], an [Option], an [Array] of
+// [Length] elements or the [Fields] of a struct.
+type Type struct {
+	Name   string
+	Vec    *Type
+	Option *Type
+	Array  *Type
+	Length int
+	Fields []Field
+}
+
+type typeJSON struct {
+	Vec    *Type   `json:"vec,omitempty"`
+	Option *Type   `json:"option,omitempty"`
+	Array  *Type   `json:"array,omitempty"`
+	Length int     `json:"length,omitempty"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+func (t *Type) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &t.Name); err == nil {
+		return nil
+	}
+	var v typeJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	t.Vec, t.Option, t.Array, t.Length, t.Fields = v.Vec, v.Option, v.Array, v.Length, v.Fields
+	return nil
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	if len(t.Name) > 0 {
+		return json.Marshal(t.Name)
+	}
+	if t.Fields != nil && len(t.Fields) == 0 {
+		return []byte(`{"fields":[]}`), nil
+	}
+	return json.Marshal(typeJSON{
+		Vec:    t.Vec,
+		Option: t.Option,
+		Array:  t.Array,
+		Length: t.Length,
+		Fields: t.Fields,
+	})
+}
+
+type Field struct {
+	Name string `json:"name"`
+	Type Type   `json:"type"`
+}
+
+type Call struct {
+	Name    string  `json:"name"`
+	Variant uint8   `json:"variant"`
+	Args    []Field `json:"args"`
+	Result  *Type   `json:"result,omitempty"` // nil if the call returns nothing
+}
+
+// argsType is the struct of the args of [c]
+func (c *Call) argsType() Type {
+	if c.Args == nil {
+		return Type{Fields: []Field{}}
+	}
+	return Type{Fields: c.Args}
+}
+
+type ABI struct {
+	Types map[string]Type `json:"types,omitempty"`
+	Calls []Call          `json:"calls"`
+	State *Type           `json:"state,omitempty"`
+}
+
+// Parse reads an ABI from JSON and checks that it is consistent.
+func Parse(b []byte) (*ABI, error) {
+	var a ABI
+	if err := json.Unmarshal(b, &a); err != nil {
+		return nil, err
+	}
+	if err := a.Verify(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Verify checks that every type is well formed and resolves, that no struct
+// contains itself and that the calls are unique.
+func (a *ABI) Verify() error {
+	for name, t := range a.Types {
+		if isPrimitive(name) {
+			return fmt.Errorf("%w: %s shadows a primitive", ErrInvalidType, name)
+		}
+		if err := a.verifyType(t, map[string]bool{name: true}); err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+	}
+	names := map[string]bool{}
+	variants := map[uint8]bool{}
+	for i := range a.Calls {
+		c := &a.Calls[i]
+		if names[c.Name] || variants[c.Variant] {
+			return fmt.Errorf("%w: %s (%d)", ErrDuplicateCall, c.Name, c.Variant)
+		}
+		names[c.Name] = true
+		variants[c.Variant] = true
+		if err := a.verifyType(c.argsType(), map[string]bool{}); err != nil {
+			return fmt.Errorf("call %s: %w", c.Name, err)
+		}
+		if c.Result != nil {
+			if err := a.verifyType(*c.Result, map[string]bool{}); err != nil {
+				return fmt.Errorf("result of %s: %w", c.Name, err)
+			}
+		}
+	}
+	if a.State != nil {
+		if err := a.verifyType(*a.State, map[string]bool{}); err != nil {
+			return fmt.Errorf("state: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifyType checks [t]. [inline] holds the named types [t] is embedded in
+// without a vec or an option in between, finding one of them again means the
+// type has an infinite size. It is nil behind a vec or an option, where named
+// types only need to exist as they are verified on their own.
+func (a *ABI) verifyType(t Type, inline map[string]bool) error {
+	set := 0
+	for _, ok := range []bool{len(t.Name) > 0, t.Vec != nil, t.Option != nil, t.Array != nil, t.Fields != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return ErrInvalidType
+	}
+	switch {
+	case len(t.Name) > 0:
+		if isPrimitive(t.Name) {
+			return nil
+		}
+		named, ok := a.Types[t.Name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownType, t.Name)
+		}
+		if inline == nil {
+			return nil
+		}
+		if inline[t.Name] {
+			return fmt.Errorf("%w: %s", ErrRecursiveType, t.Name)
+		}
+		inline[t.Name] = true
+		defer delete(inline, t.Name)
+		return a.verifyType(named, inline)
+	case t.Vec != nil:
+		return a.verifyType(*t.Vec, nil)
+	case t.Option != nil:
+		return a.verifyType(*t.Option, nil)
+	case t.Array != nil:
+		if t.Length <= 0 {
+			return fmt.Errorf("%w: array length %d", ErrInvalidType, t.Length)
+		}
+		return a.verifyType(*t.Array, inline)
+	default:
+		seen := map[string]bool{}
+		for _, f := range t.Fields {
+			if len(f.Name) == 0 || seen[f.Name] {
+				return fmt.Errorf("%w: field %q", ErrInvalidType, f.Name)
+			}
+			seen[f.Name] = true
+			if err := a.verifyType(f.Type, inline); err != nil {
+				return fmt.Errorf("field %s: %w", f.Name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Call returns the call named [name].
+func (a *ABI) Call(name string) (*Call, error) {
+	for i := range a.Calls {
+		if a.Calls[i].Name == name {
+			return &a.Calls[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCall, name)
+}
+
+func (a *ABI) callByVariant(variant uint8) (*Call, error) {
+	for i := range a.Calls {
+		if a.Calls[i].Variant == variant {
+			return &a.Calls[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: variant %d", ErrUnknownCall, variant)
+}
+
+// EncodeCall returns the payload calling [name] with [args], a JSON object
+// holding every argument.
+func (a *ABI) EncodeCall(name string, args []byte) ([]byte, error) {
+	c, err := a.Call(name)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := a.encode(c.argsType(), args)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{c.Variant}, payload...), nil
+}
+
+// DecodeCall returns the name of the call [payload] makes and its args as a
+// JSON object.
+func (a *ABI) DecodeCall(payload []byte) (string, []byte, error) {
+	if len(payload) == 0 {
+		return "", nil, ErrShortInput
+	}
+	c, err := a.callByVariant(payload[0])
+	if err != nil {
+		return "", nil, err
+	}
+	args, err := a.decode(c.argsType(), payload[1:])
+	if err != nil {
+		return "", nil, err
+	}
+	return c.Name, args, nil
+}
+
+// EncodeResult encodes the JSON [value] returned by the call [name].
+func (a *ABI) EncodeResult(name string, value []byte) ([]byte, error) {
+	c, err := a.Call(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Result == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoResult, name)
+	}
+	return a.encode(*c.Result, value)
+}
+
+// DecodeResult returns the [result] of the call [name] as JSON.
+func (a *ABI) DecodeResult(name string, result []byte) ([]byte, error) {
+	c, err := a.Call(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Result == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoResult, name)
+	}
+	return a.decode(*c.Result, result)
+}
+
+// EncodeState encodes the JSON [value] of the contract state.
+func (a *ABI) EncodeState(value []byte) ([]byte, error) {
+	if a.State == nil {
+		return nil, ErrNoState
+	}
+	return a.encode(*a.State, value)
+}
+
+// DecodeState returns the contract [state] as JSON.
+func (a *ABI) DecodeState(state []byte) ([]byte, error) {
+	if a.State == nil {
+		return nil, ErrNoState
+	}
+	return a.decode(*a.State, state)
+}
@@ -0,0 +1,180 @@
+package abi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/containerman17/avalanche-polyglot-subnet/abi"
+	"github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/near/borsh-go"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `{
+  "types": {
+    "Point": {"fields": [{"name": "x", "type": "i32"}, {"name": "y", "type": "i32"}]},
+    "Tree": {"fields": [{"name": "value", "type": "u8"}, {"name": "children", "type": {"vec": "Tree"}}]}
+  },
+  "calls": [
+    {"name": "everything", "variant": 7, "args": [
+      {"name": "flag", "type": "bool"},
+      {"name": "small", "type": "u8"},
+      {"name": "medium", "type": "u16"},
+      {"name": "large", "type": "u32"},
+      {"name": "huge", "type": "u64"},
+      {"name": "negative", "type": "i64"},
+      {"name": "ratio", "type": "f64"},
+      {"name": "label", "type": "string"},
+      {"name": "data", "type": "bytes"},
+      {"name": "hash", "type": {"array": "u8", "length": 4}},
+      {"name": "points", "type": {"vec": "Point"}},
+      {"name": "origin", "type": {"option": "Point"}},
+      {"name": "missing", "type": {"option": "u16"}}
+    ]},
+    {"name": "nothing", "variant": 0, "args": [], "result": "Tree"}
+  ],
+  "state": {"vec": "address"}
+}`
+
+type point struct {
+	X int32
+	Y int32
+}
+
+type everythingArgs struct {
+	Flag     bool
+	Small    uint8
+	Medium   uint16
+	Large    uint32
+	Huge     uint64
+	Negative int64
+	Ratio    float64
+	Label    string
+	Data     []byte
+	Hash     [4]byte
+	Points   []point
+	Origin   *point
+	Missing  *uint16
+}
+
+func TestEncodeMatchesBorsh(t *testing.T) {
+	a, err := abi.Parse([]byte(testABI))
+	require.NoError(t, err)
+
+	args := `{"flag":true,"small":255,"medium":65535,"large":7,"huge":"18446744073709551615",` +
+		`"negative":-42,"ratio":0.5,"label":"hé","data":"0x0102","hash":"0xdeadbeef",` +
+		`"points":[{"x":1,"y":-1},{"x":2,"y":-2}],"origin":{"x":0,"y":3},"missing":null}`
+	payload, err := a.EncodeCall("everything", []byte(args))
+	require.NoError(t, err)
+
+	expected, err := borsh.Serialize(everythingArgs{
+		Flag:     true,
+		Small:    255,
+		Medium:   65535,
+		Large:    7,
+		Huge:     18446744073709551615,
+		Negative: -42,
+		Ratio:    0.5,
+		Label:    "hé",
+		Data:     []byte{1, 2},
+		Hash:     [4]byte{0xde, 0xad, 0xbe, 0xef},
+		Points:   []point{{1, -1}, {2, -2}},
+		Origin:   &point{0, 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{7}, expected...), payload)
+
+	name, decoded, err := a.DecodeCall(payload)
+	require.NoError(t, err)
+	require.Equal(t, "everything", name)
+	require.JSONEq(t, `{"flag":true,"small":255,"medium":65535,"large":7,"huge":18446744073709551615,`+
+		`"negative":-42,"ratio":0.5,"label":"hé","data":"0x0102","hash":"0xdeadbeef",`+
+		`"points":[{"x":1,"y":-1},{"x":2,"y":-2}],"origin":{"x":0,"y":3},"missing":null}`, string(decoded))
+}
+
+func TestResultAndState(t *testing.T) {
+	a, err := abi.Parse([]byte(testABI))
+	require.NoError(t, err)
+
+	tree := `{"value":1,"children":[{"value":2,"children":[]},{"value":3,"children":[{"value":4,"children":[]}]}]}`
+	result, err := a.EncodeResult("nothing", []byte(tree))
+	require.NoError(t, err)
+	decoded, err := a.DecodeResult("nothing", result)
+	require.NoError(t, err)
+	require.Equal(t, tree, string(decoded)) // fields keep their order
+
+	_, err = a.EncodeResult("everything", []byte(`{}`))
+	require.ErrorIs(t, err, abi.ErrNoResult)
+
+	addr := codec.MustAddressBech32(consts.HRP, codec.Address{1, 2, 3})
+	state, err := a.EncodeState([]byte(`["` + addr + `"]`))
+	require.NoError(t, err)
+	require.Len(t, state, 4+codec.AddressLen)
+	decoded, err = a.DecodeState(state)
+	require.NoError(t, err)
+	var addrs []string
+	require.NoError(t, json.Unmarshal(decoded, &addrs))
+	require.Equal(t, []string{addr}, addrs)
+}
+
+func TestCodecErrors(t *testing.T) {
+	a, err := abi.Parse([]byte(testABI))
+	require.NoError(t, err)
+
+	_, err = a.EncodeCall("unknown", []byte(`{}`))
+	require.ErrorIs(t, err, abi.ErrUnknownCall)
+	_, err = a.EncodeCall("nothing", []byte(`{"extra":1}`))
+	require.ErrorIs(t, err, abi.ErrUnknownField)
+	_, err = a.EncodeResult("nothing", []byte(`{"value":1}`))
+	require.ErrorIs(t, err, abi.ErrMissingField)
+	_, err = a.EncodeResult("nothing", []byte(`{"value":256,"children":[]}`))
+	require.ErrorIs(t, err, abi.ErrInvalidValue)
+
+	_, _, err = a.DecodeCall([]byte{0, 1})
+	require.ErrorIs(t, err, abi.ErrTrailingBytes)
+	_, _, err = a.DecodeCall([]byte{5})
+	require.ErrorIs(t, err, abi.ErrUnknownCall)
+	_, _, err = a.DecodeCall(nil)
+	require.ErrorIs(t, err, abi.ErrShortInput)
+	// a vec can't claim more items than there are bytes left
+	_, err = a.DecodeResult("nothing", []byte{1, 0xff, 0xff, 0xff, 0xff})
+	require.ErrorIs(t, err, abi.ErrShortInput)
+}
+
+func TestVerify(t *testing.T) {
+	for name, tt := range map[string]struct {
+		abi string
+		err error
+	}{
+		"unknown type": {
+			abi: `{"calls": [{"name": "a", "variant": 0, "args": [{"name": "x", "type": "Missing"}]}]}`,
+			err: abi.ErrUnknownType,
+		},
+		"recursive struct": {
+			abi: `{"types": {"A": {"fields": [{"name": "a", "type": {"array": "A", "length": 1}}]}}, "calls": []}`,
+			err: abi.ErrRecursiveType,
+		},
+		"duplicate variant": {
+			abi: `{"calls": [{"name": "a", "variant": 0}, {"name": "b", "variant": 0}]}`,
+			err: abi.ErrDuplicateCall,
+		},
+		"shadowed primitive": {
+			abi: `{"types": {"u8": {"fields": []}}, "calls": []}`,
+			err: abi.ErrInvalidType,
+		},
+		"empty array": {
+			abi: `{"calls": [], "state": {"array": "u8", "length": 0}}`,
+			err: abi.ErrInvalidType,
+		},
+		"ambiguous type": {
+			abi: `{"calls": [], "state": {"vec": "u8", "option": "u8"}}`,
+			err: abi.ErrInvalidType,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := abi.Parse([]byte(tt.abi))
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,485 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/consts"
+)
+
+var (
+	ErrInvalidValue  = errors.New("value does not match the type")
+	ErrMissingField  = errors.New("missing field")
+	ErrUnknownField  = errors.New("unknown field")
+	ErrTrailingBytes = errors.New("trailing bytes")
+	ErrShortInput    = errors.New("input too short")
+)
+
+// encode returns the borsh encoding of the JSON [value] of type [t]
+func (a *ABI) encode(t Type, value []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: more than one JSON value", ErrInvalidValue)
+	}
+	e := &encoder{a: a}
+	if err := e.value(t, v); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+// decode returns [b], the borsh encoding of a value of type [t], as JSON
+func (a *ABI) decode(t Type, b []byte) ([]byte, error) {
+	d := &decoder{a: a, b: b}
+	v, err := d.value(t)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.b) > 0 {
+		return nil, fmt.Errorf("%w: %d", ErrTrailingBytes, len(d.b))
+	}
+	return json.Marshal(v)
+}
+
+// isBytes reports whether [t] is a sequence of u8, encoded as hex in JSON
+func isBytes(t *Type) bool {
+	return t != nil && t.Name == U8
+}
+
+func intBits(name string) int {
+	switch name {
+	case U8, I8:
+		return 8
+	case U16, I16:
+		return 16
+	case U32, I32, F32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+type encoder struct {
+	a *ABI
+	b []byte
+}
+
+func (e *encoder) value(t Type, v any) error {
+	switch {
+	case len(t.Name) > 0:
+		return e.named(t.Name, v)
+	case t.Vec != nil:
+		if isBytes(t.Vec) {
+			return e.named(Bytes, v)
+		}
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%w: expected an array", ErrInvalidValue)
+		}
+		e.b = binary.LittleEndian.AppendUint32(e.b, uint32(len(items)))
+		for i, item := range items {
+			if err := e.value(*t.Vec, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return nil
+	case t.Option != nil:
+		if v == nil {
+			e.b = append(e.b, 0)
+			return nil
+		}
+		e.b = append(e.b, 1)
+		return e.value(*t.Option, v)
+	case t.Array != nil:
+		if isBytes(t.Array) {
+			b, err := parseHex(v)
+			if err != nil {
+				return err
+			}
+			if len(b) != t.Length {
+				return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidValue, t.Length, len(b))
+			}
+			e.b = append(e.b, b...)
+			return nil
+		}
+		items, ok := v.([]any)
+		if !ok || len(items) != t.Length {
+			return fmt.Errorf("%w: expected an array of %d items", ErrInvalidValue, t.Length)
+		}
+		for i, item := range items {
+			if err := e.value(*t.Array, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return nil
+	default:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: expected an object", ErrInvalidValue)
+		}
+		for _, f := range t.Fields {
+			fv, ok := obj[f.Name]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrMissingField, f.Name)
+			}
+			if err := e.value(f.Type, fv); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		if len(obj) != len(t.Fields) {
+			for name := range obj {
+				if !hasField(t.Fields, name) {
+					return fmt.Errorf("%w: %s", ErrUnknownField, name)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func hasField(fields []Field, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUint appends the [size] low bytes of [n] in little endian
+func appendUint(b []byte, n uint64, size int) []byte {
+	for i := 0; i < size; i++ {
+		b = append(b, byte(n>>(8*i)))
+	}
+	return b
+}
+
+func (e *encoder) named(name string, v any) error {
+	switch name {
+	case Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%w: expected a bool", ErrInvalidValue)
+		}
+		if b {
+			e.b = append(e.b, 1)
+		} else {
+			e.b = append(e.b, 0)
+		}
+		return nil
+	case U8, U16, U32, U64:
+		s, err := numberString(v)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.ParseUint(s, 10, intBits(name))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+		}
+		e.b = appendUint(e.b, n, intBits(name)/8)
+		return nil
+	case I8, I16, I32, I64:
+		s, err := numberString(v)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(s, 10, intBits(name))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+		}
+		e.b = appendUint(e.b, uint64(n), intBits(name)/8)
+		return nil
+	case F32, F64:
+		s, err := numberString(v)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(s, intBits(name))
+		if err != nil || math.IsNaN(f) {
+			return fmt.Errorf("%w: invalid float %s", ErrInvalidValue, s)
+		}
+		if name == F32 {
+			e.b = binary.LittleEndian.AppendUint32(e.b, math.Float32bits(float32(f)))
+		} else {
+			e.b = binary.LittleEndian.AppendUint64(e.b, math.Float64bits(f))
+		}
+		return nil
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%w: expected a string", ErrInvalidValue)
+		}
+		e.b = binary.LittleEndian.AppendUint32(e.b, uint32(len(s)))
+		e.b = append(e.b, s...)
+		return nil
+	case Bytes:
+		b, err := parseHex(v)
+		if err != nil {
+			return err
+		}
+		e.b = binary.LittleEndian.AppendUint32(e.b, uint32(len(b)))
+		e.b = append(e.b, b...)
+		return nil
+	case Address:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%w: expected an address", ErrInvalidValue)
+		}
+		addr, err := codec.ParseAddressBech32(consts.HRP, s)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+		}
+		e.b = append(e.b, addr[:]...)
+		return nil
+	default:
+		return e.value(e.a.Types[name], v)
+	}
+}
+
+// numberString accepts JSON numbers and strings holding one, so integers
+// beyond 2^53 can be written exactly
+func numberString(v any) (string, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.String(), nil
+	case string:
+		return n, nil
+	default:
+		return "", fmt.Errorf("%w: expected a number", ErrInvalidValue)
+	}
+}
+
+func parseHex(v any) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a hex string", ErrInvalidValue)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return b, nil
+}
+
+// object is a decoded struct, it keeps the order of the fields
+type object []objectField
+
+type objectField struct {
+	name  string
+	value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+	for i, f := range o {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		b = append(append(append(b, name...), ':'), value...)
+	}
+	return append(b, '}'), nil
+}
+
+type decoder struct {
+	a *ABI
+	b []byte
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.b) {
+		return nil, ErrShortInput
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b, nil
+}
+
+// length reads the length of a vec or a string. It can't exceed the bytes
+// left, which bounds the work done for a short input.
+func (d *decoder) length() (int, error) {
+	b, err := d.read(hconsts.Uint32Len)
+	if err != nil {
+		return 0, err
+	}
+	n := binary.LittleEndian.Uint32(b)
+	if uint64(n) > uint64(len(d.b)) {
+		return 0, ErrShortInput
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value(t Type) (any, error) {
+	switch {
+	case len(t.Name) > 0:
+		return d.named(t.Name)
+	case t.Vec != nil:
+		if isBytes(t.Vec) {
+			return d.named(Bytes)
+		}
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := d.value(*t.Vec)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case t.Option != nil:
+		tag, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		switch tag[0] {
+		case 0:
+			return nil, nil
+		case 1:
+			return d.value(*t.Option)
+		default:
+			return nil, fmt.Errorf("%w: option tag %d", ErrInvalidValue, tag[0])
+		}
+	case t.Array != nil:
+		if isBytes(t.Array) {
+			b, err := d.read(t.Length)
+			if err != nil {
+				return nil, err
+			}
+			return "0x" + hex.EncodeToString(b), nil
+		}
+		items := make([]any, 0, t.Length)
+		for i := 0; i < t.Length; i++ {
+			item, err := d.value(*t.Array)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		obj := make(object, 0, len(t.Fields))
+		for _, f := range t.Fields {
+			v, err := d.value(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			obj = append(obj, objectField{name: f.Name, value: v})
+		}
+		return obj, nil
+	}
+}
+
+func (d *decoder) named(name string) (any, error) {
+	switch name {
+	case Bool:
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		switch b[0] {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		default:
+			return nil, fmt.Errorf("%w: bool %d", ErrInvalidValue, b[0])
+		}
+	case U8, U16, U32, U64, I8, I16, I32, I64:
+		b, err := d.read(intBits(name) / 8)
+		if err != nil {
+			return nil, err
+		}
+		var buf [8]byte
+		copy(buf[:], b)
+		n := binary.LittleEndian.Uint64(buf[:])
+		switch name {
+		case I8:
+			return json.Number(strconv.FormatInt(int64(int8(n)), 10)), nil
+		case I16:
+			return json.Number(strconv.FormatInt(int64(int16(n)), 10)), nil
+		case I32:
+			return json.Number(strconv.FormatInt(int64(int32(n)), 10)), nil
+		case I64:
+			return json.Number(strconv.FormatInt(int64(n), 10)), nil
+		default:
+			return json.Number(strconv.FormatUint(n, 10)), nil
+		}
+	case F32:
+		b, err := d.read(hconsts.Uint32Len)
+		if err != nil {
+			return nil, err
+		}
+		f := math.Float32frombits(binary.LittleEndian.Uint32(b))
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			return nil, fmt.Errorf("%w: float %v", ErrInvalidValue, f)
+		}
+		return json.Number(strconv.FormatFloat(float64(f), 'g', -1, 32)), nil
+	case F64:
+		b, err := d.read(hconsts.Uint64Len)
+		if err != nil {
+			return nil, err
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("%w: float %v", ErrInvalidValue, f)
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case String:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("%w: invalid utf-8", ErrInvalidValue)
+		}
+		return string(b), nil
+	case Bytes:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return "0x" + hex.EncodeToString(b), nil
+	case Address:
+		b, err := d.read(codec.AddressLen)
+		if err != nil {
+			return nil, err
+		}
+		return codec.MustAddressBech32(consts.HRP, codec.Address(b)), nil
+	default:
+		return d.value(d.a.Types[name])
+	}
+}
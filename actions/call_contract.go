@@ -0,0 +1,323 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var _ chain.Action = (*CallContract)(nil)
+
+var (
+	ErrStorageKeyNotDeclared = errors.New("storage key not declared")
+	ErrRecipientNotDeclared  = errors.New("transfer recipient not declared")
+	ErrStateWriteNotDeclared = errors.New("state write not declared")
+)
+
+// contractMaxTime only protects this node from calls that are too slow to
+// execute. It must not affect the outcome of a call, so the calls in blocks
+// only stop on fuel and memory: the builder drops the calls of its mempool
+// that run longer instead, see [ScreenCallContract].
+var contractMaxTime = DefaultContractMaxTime
+
+// SetContractMaxTime sets the wall-clock limit of contract calls. It must be
+// called before any contract is executed.
+func SetContractMaxTime(maxTime time.Duration) {
+	contractMaxTime = maxTime
+}
+
+func ContractMaxTime() time.Duration {
+	return contractMaxTime
+}
+
+type CallContract struct {
+	// ContractAddress is the contract to invoke.
+	ContractAddress codec.Address `json:"contractAddress"`
+
+	// Payload is passed to the contract as is.
+	Payload []byte `json:"payload"`
+
+	// MaxFuel is the most fuel the call may consume. It determines the
+	// compute units reserved for the call.
+	MaxFuel uint64 `json:"maxFuel"`
+
+	// StorageKeys are the keys of the contract storage the call may read or
+	// write. Declaring them lets calls touching different keys run in
+	// parallel.
+	StorageKeys [][]byte `json:"storageKeys"`
+
+	// WritesState lets the call update the state of the contract or destruct
+	// it. Calls that don't set it only read the state, bytecode and metadata
+	// of the contract, so they don't conflict with each other.
+	WritesState bool `json:"writesState"`
+
+	// Value is transferred from the actor to the contract before the call.
+	Value uint64 `json:"value"`
+
+	// Recipients are the addresses the contract may transfer funds to during
+	// the call, on top of the contracts of the call.
+	Recipients []codec.Address `json:"recipients"`
+
+	// Callees are the contracts the call may invoke, directly or through
+	// other callees, with their storage keys.
+	Callees []Callee `json:"callees"`
+
+	// Codes are the code hashes of the contracts of the call deployed from
+	// the code store, see [CreateContractFromCode].
+	Codes []ids.ID `json:"codes"`
+
+	// screened is set once this node checked the call against its wall-clock
+	// limit. It only lives in the mempool copy of the transaction.
+	screened atomic.Bool
+}
+
+// Callee declares a contract a [CallContract] may invoke.
+type Callee struct {
+	Address     codec.Address `json:"address"`
+	StorageKeys [][]byte      `json:"storageKeys"`
+	WritesState bool          `json:"writesState"` // see [CallContract.WritesState]
+}
+
+func (*CallContract) GetTypeID() uint8 {
+	return mconsts.CallContractID
+}
+
+func (t *CallContract) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	keys := state.Keys{
+		string(storage.BalanceKey(t.ContractAddress)):         state.All,
+		string(storage.ContractDepositKey(t.ContractAddress)): state.All,
+		// pays the value and the storage deposits
+		string(storage.BalanceKey(actor)): state.Read | state.Write,
+	}
+	addContractKeys(keys, t.ContractAddress, t.WritesState)
+	for _, key := range t.StorageKeys {
+		keys.Add(string(storage.ContractStorageKey(t.ContractAddress, key)), state.All)
+	}
+	for _, recipient := range t.Recipients {
+		keys.Add(string(storage.BalanceKey(recipient)), state.All)
+	}
+	for _, callee := range t.Callees {
+		addContractKeys(keys, callee.Address, callee.WritesState)
+		keys.Add(string(storage.BalanceKey(callee.Address)), state.All)
+		keys.Add(string(storage.ContractDepositKey(callee.Address)), state.All)
+		for _, key := range callee.StorageKeys {
+			keys.Add(string(storage.ContractStorageKey(callee.Address, key)), state.All)
+		}
+	}
+	for _, hash := range t.Codes {
+		keys.Add(string(storage.CodeKey(hash)), state.Read)
+	}
+	return keys
+}
+
+// addContractKeys declares the bytecode, metadata and state of the contract at
+// [addr]. A contract destructing itself removes them, so they are all writable
+// if [writesState].
+func addContractKeys(keys state.Keys, addr codec.Address, writesState bool) {
+	permissions := state.Read
+	if writesState {
+		permissions |= state.Write
+	}
+	keys.Add(string(storage.ContractBytecodeKey(addr)), permissions)
+	keys.Add(string(storage.ContractMetadataKey(addr)), permissions)
+	keys.Add(string(storage.ContractStateKey(addr)), permissions)
+}
+
+func (t *CallContract) StateKeysMaxChunks() []uint16 {
+	chunks := []uint16{
+		storage.ContractBytecodeChunks,
+		storage.ContractMetadataChunks,
+		storage.ContractStateChunks,
+		storage.BalanceChunks,
+		storage.ContractDepositChunks,
+		storage.BalanceChunks,
+	}
+	for range t.StorageKeys {
+		chunks = append(chunks, storage.ContractStorageChunks)
+	}
+	for range t.Recipients {
+		chunks = append(chunks, storage.BalanceChunks)
+	}
+	for _, callee := range t.Callees {
+		chunks = append(chunks, storage.ContractBytecodeChunks, storage.ContractMetadataChunks, storage.ContractStateChunks, storage.BalanceChunks, storage.ContractDepositChunks)
+		for range callee.StorageKeys {
+			chunks = append(chunks, storage.ContractStorageChunks)
+		}
+	}
+	for range t.Codes {
+		chunks = append(chunks, storage.CodeChunks)
+	}
+	return chunks
+}
+
+func (*CallContract) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *CallContract) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	rules := r.(ContractRules)
+	if t.MaxFuel > rules.GetContractMaxFuel() {
+		return false, CallContractComputeUnits, OutputMaxFuelExceeded, nil, nil
+	}
+
+	call := &contractCall{
+		ctx:       ctx,
+		rules:     rules,
+		timestamp: timestamp,
+		txID:      txID,
+		payer:     actor,
+		access:    t,
+	}
+	out, err := call.run(mu, actor, t.ContractAddress, t.Payload, t.Value, t.MaxFuel)
+	if out == nil {
+		return false, CallContractComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	computeUnits := CallContractComputeUnits + FuelComputeUnits(rules, out.fuelConsumed)
+	if err != nil {
+		return false, computeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	output := &CallContractOutput{Result: out.result, Events: out.events}
+	return true, computeUnits, output.Bytes(), nil, nil
+}
+
+func (t *CallContract) MaxComputeUnits(r chain.Rules) uint64 {
+	return CallContractComputeUnits + FuelComputeUnits(r.(ContractRules), t.MaxFuel)
+}
+
+func (t *CallContract) Size() int {
+	size := codec.AddressLen + codec.BytesLen(t.Payload) + consts.Uint64Len + consts.IntLen
+	for _, key := range t.StorageKeys {
+		size += codec.BytesLen(key)
+	}
+	size += consts.BoolLen + consts.Uint64Len + consts.IntLen + len(t.Recipients)*codec.AddressLen + consts.IntLen
+	for _, callee := range t.Callees {
+		size += codec.AddressLen + consts.IntLen + consts.BoolLen
+		for _, key := range callee.StorageKeys {
+			size += codec.BytesLen(key)
+		}
+	}
+	return size + consts.IntLen + len(t.Codes)*consts.IDLen
+}
+
+func (t *CallContract) Marshal(p *codec.Packer) {
+	p.PackAddress(t.ContractAddress)
+	p.PackBytes(t.Payload)
+	p.PackUint64(t.MaxFuel)
+	packStorageKeys(p, t.StorageKeys)
+	p.PackBool(t.WritesState)
+	p.PackUint64(t.Value)
+	p.PackInt(len(t.Recipients))
+	for _, recipient := range t.Recipients {
+		p.PackAddress(recipient)
+	}
+	p.PackInt(len(t.Callees))
+	for _, callee := range t.Callees {
+		p.PackAddress(callee.Address)
+		packStorageKeys(p, callee.StorageKeys)
+		p.PackBool(callee.WritesState)
+	}
+	p.PackInt(len(t.Codes))
+	for _, hash := range t.Codes {
+		p.PackID(hash)
+	}
+}
+
+func UnmarshalCallContract(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var action CallContract
+	p.UnpackAddress(&action.ContractAddress)
+	p.UnpackBytes(-1, false, &action.Payload)
+	action.MaxFuel = p.UnpackUint64(true)
+	storageKeys, err := unpackStorageKeys(p)
+	if err != nil {
+		return nil, err
+	}
+	action.StorageKeys = storageKeys
+	action.WritesState = p.UnpackBool()
+	action.Value = p.UnpackUint64(false)
+	recipientCount := p.UnpackInt(false)
+	if recipientCount > CallContractMaxRecipients {
+		return nil, fmt.Errorf("%d recipients exceed the limit of %d", recipientCount, CallContractMaxRecipients)
+	}
+	for i := 0; i < recipientCount; i++ {
+		var recipient codec.Address
+		p.UnpackAddress(&recipient)
+		action.Recipients = append(action.Recipients, recipient)
+	}
+	calleeCount := p.UnpackInt(false)
+	if calleeCount > CallContractMaxCallees {
+		return nil, fmt.Errorf("%d callees exceed the limit of %d", calleeCount, CallContractMaxCallees)
+	}
+	for i := 0; i < calleeCount; i++ {
+		var callee Callee
+		p.UnpackAddress(&callee.Address)
+		callee.StorageKeys, err = unpackStorageKeys(p)
+		if err != nil {
+			return nil, err
+		}
+		callee.WritesState = p.UnpackBool()
+		action.Callees = append(action.Callees, callee)
+	}
+	codeCount := p.UnpackInt(false)
+	if codeCount > CallContractMaxCodes {
+		return nil, fmt.Errorf("%d codes exceed the limit of %d", codeCount, CallContractMaxCodes)
+	}
+	for i := 0; i < codeCount; i++ {
+		var hash ids.ID
+		p.UnpackID(true, &hash)
+		action.Codes = append(action.Codes, hash)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func packStorageKeys(p *codec.Packer, keys [][]byte) {
+	p.PackInt(len(keys))
+	for _, key := range keys {
+		p.PackBytes(key)
+	}
+}
+
+func unpackStorageKeys(p *codec.Packer) ([][]byte, error) {
+	count := p.UnpackInt(false)
+	if count > CallContractMaxStorageKeys {
+		return nil, fmt.Errorf("%d storage keys exceed the limit of %d", count, CallContractMaxStorageKeys)
+	}
+	var keys [][]byte
+	for i := 0; i < count; i++ {
+		var key []byte
+		p.UnpackBytes(storage.MaxContractStorageKeyLen, true, &key)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (t *CallContract) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"errors"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+)
+
+var ErrInvalidOutput = errors.New("invalid call contract output")
+
+// Event is emitted by a contract during a successful call.
+type Event struct {
+	Contract codec.Address `json:"contract"`
+	Topic    []byte        `json:"topic"`
+	Data     []byte        `json:"data"`
+}
+
+// CallContractOutput is the output of a successful [CallContract]. A failed
+// call outputs its error message instead.
+type CallContractOutput struct {
+	// Result is set by the called contract.
+	Result []byte `json:"result"`
+
+	// Events are emitted by the called contract and its callees, in order.
+	// The events of failed nested calls are discarded.
+	Events []Event `json:"events"`
+}
+
+func (o *CallContractOutput) Size() int {
+	size := codec.BytesLen(o.Result) + consts.IntLen
+	for _, event := range o.Events {
+		size += codec.AddressLen + codec.BytesLen(event.Topic) + codec.BytesLen(event.Data)
+	}
+	return size
+}
+
+// Bytes returns nil for an empty output (see [chain.Transaction.Execute]).
+func (o *CallContractOutput) Bytes() []byte {
+	if len(o.Result) == 0 && len(o.Events) == 0 {
+		return nil
+	}
+	p := codec.NewWriter(o.Size(), consts.MaxInt)
+	p.PackBytes(o.Result)
+	p.PackInt(len(o.Events))
+	for _, event := range o.Events {
+		p.PackAddress(event.Contract)
+		p.PackBytes(event.Topic)
+		p.PackBytes(event.Data)
+	}
+	return p.Bytes()
+}
+
+// UnmarshalCallContractOutput decodes the output of a successful
+// [CallContract].
+func UnmarshalCallContractOutput(b []byte) (*CallContractOutput, error) {
+	var output CallContractOutput
+	if len(b) == 0 {
+		return &output, nil
+	}
+	p := codec.NewReader(b, consts.MaxInt)
+	p.UnpackBytes(-1, false, &output.Result)
+	eventCount := p.UnpackInt(false)
+	for i := 0; i < eventCount && p.Err() == nil; i++ {
+		var event Event
+		p.UnpackAddress(&event.Contract)
+		p.UnpackBytes(execution.MaxEventTopicLen, true, &event.Topic)
+		p.UnpackBytes(-1, false, &event.Data)
+		output.Events = append(output.Events, event)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidOutput
+	}
+	return &output, nil
+}
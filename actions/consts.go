@@ -3,4 +3,37 @@
 
 package actions
 
+import "time"
+
 const TransferComputeUnits = 1
+
+const (
+	// CallContractComputeUnits is charged on top of the fuel consumed by a call
+	CallContractComputeUnits = 1
+
+	// CallContractMaxStorageKeys bounds the storage keys a call can declare
+	CallContractMaxStorageKeys = 64
+
+	// CallContractMaxRecipients bounds the transfer recipients a call can
+	// declare
+	CallContractMaxRecipients = 16
+
+	// CallContractMaxCallees bounds the contracts a call can declare
+	CallContractMaxCallees = 16
+
+	// CallContractMaxCodes bounds the code hashes a call can declare
+	CallContractMaxCodes = 16
+
+	// CallContractMaxEvents bounds the events emitted by a call and its
+	// callees
+	CallContractMaxEvents = 256
+
+	// Execution limits applied to every contract call (fuel is set by the caller
+	// and bounded by [ContractRules.GetContractMaxFuel])
+	ContractMaxMemory = 100 * 1024 * 1024 // 100MB
+	ContractMaxOutput = 1024 * 1024       // 1MB, fits the base64 encoded end state
+
+	// DefaultContractMaxTime is the default node-local wall-clock limit, see
+	// [SetContractMaxTime]
+	DefaultContractMaxTime = 100 * time.Millisecond
+)
@@ -0,0 +1,460 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var (
+	ErrCalleeNotDeclared = errors.New("callee not declared")
+	ErrReentrantCall     = errors.New("reentrant contract call")
+	ErrCallDepthExceeded = errors.New("max call depth exceeded")
+	ErrTooManyEvents     = errors.New("too many events")
+	ErrCodeNotDeclared   = errors.New("code not declared")
+)
+
+// contractCall executes a contract call and the nested calls it makes. The
+// calls of a tree share the wall-clock limit, if any, and may only access the
+// state declared by [access].
+type contractCall struct {
+	ctx       context.Context
+	rules     ContractRules
+	timestamp int64
+	txID      ids.ID
+	deadline  time.Time // zero for the calls in blocks, see [contractMaxTime]
+
+	// payer locks the storage deposits of the tree, it is the actor of the
+	// transaction
+	payer codec.Address
+
+	// access is nil when every key may be accessed, for simulations
+	access *CallContract
+
+	// stack holds the contracts being executed, a contract can't be called
+	// again before it returns
+	stack  []codec.Address
+	calls  uint32
+	events int // kept by the whole tree, bounded by CallContractMaxEvents
+}
+
+type callOutput struct {
+	result       []byte
+	stdErr       []byte
+	fuelConsumed uint64
+	events       []Event
+}
+
+// run executes the contract at [addr] as [caller] and applies its changes to
+// [mu]. The output is nil if the contract could not be executed, otherwise it
+// holds the fuel consumed, even if the call failed.
+func (c *contractCall) run(
+	mu state.Mutable,
+	caller codec.Address,
+	addr codec.Address,
+	input []byte,
+	value uint64,
+	maxFuel uint64,
+) (*callOutput, error) {
+	if slices.Contains(c.stack, addr) {
+		return nil, ErrReentrantCall
+	}
+	if len(c.stack) > int(c.rules.GetContractMaxCallDepth()) {
+		return nil, ErrCallDepthExceeded
+	}
+	if len(c.stack) > 0 && !c.declaresCallee(addr) {
+		return nil, ErrCalleeNotDeclared
+	}
+
+	metadata, err := storage.GetContractMetadata(c.ctx, mu, addr)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := mconsts.EngineRegistry.Get(metadata.EngineID)
+	if err != nil {
+		return nil, err
+	}
+	bytecode, err := c.loadBytecode(mu, addr, metadata)
+	if err != nil {
+		return nil, err
+	}
+	contractState, err := storage.GetContractState(c.ctx, mu, addr)
+	if err != nil {
+		return nil, err
+	}
+	if value > 0 {
+		if err := storage.SubBalance(c.ctx, mu, caller, value); err != nil {
+			return nil, err
+		}
+		if err := storage.AddBalance(c.ctx, mu, addr, value, true); err != nil {
+			return nil, err
+		}
+	}
+	var maxTime time.Duration
+	if !c.deadline.IsZero() {
+		maxTime = time.Until(c.deadline)
+		if maxTime <= 0 {
+			return nil, execution.ErrTimeout
+		}
+	}
+
+	c.stack = append(c.stack, addr)
+	defer func() {
+		c.stack = c.stack[:len(c.stack)-1]
+	}()
+	chain := &callChain{call: c, mu: mu, addr: addr}
+	res, err := engine.Execute(execution.ExecParams{
+		MaxFuel:      maxFuel,
+		MaxTime:      maxTime,
+		MaxMemory:    ContractMaxMemory,
+		MaxOutput:    ContractMaxOutput,
+		Timestamp:    c.timestamp,
+		Seed:         c.nextSeed(),
+		Bytecode:     &bytecode,
+		Storage:      &contractStorage{ctx: c.ctx, im: mu, call: c, addr: addr},
+		Value:        value,
+		Chain:        chain,
+		CurrentState: contractState,
+		Payload:      input,
+		Actor:        caller[:],
+	})
+	if errors.Is(err, execution.ErrTimeout) {
+		return nil, err
+	}
+	if err != nil {
+		// A contract that could not even start is charged its whole budget.
+		if res == nil {
+			return &callOutput{fuelConsumed: maxFuel}, err
+		}
+		return &callOutput{stdErr: res.StdErr, fuelConsumed: res.FuelConsumed}, err
+	}
+	out := &callOutput{
+		result:       res.Result,
+		stdErr:       res.StdErr,
+		fuelConsumed: res.FuelConsumed,
+		events:       chain.events,
+	}
+
+	if (res.UpdatedState != nil || res.SelfDestruct != nil) && !c.declaresStateWrite(addr) {
+		return out, ErrStateWriteNotDeclared
+	}
+	if res.UpdatedState != nil {
+		if err := updateStateDeposit(c.ctx, c.rules, mu, addr, c.payer, contractState, *res.UpdatedState); err != nil {
+			return out, err
+		}
+		if err := storage.SetContractState(c.ctx, mu, addr, *res.UpdatedState); err != nil {
+			return out, err
+		}
+	}
+	// sorted, so the writes happen in the same order on every node
+	writtenKeys := make([]string, 0, len(res.StorageWrites))
+	for key := range res.StorageWrites {
+		writtenKeys = append(writtenKeys, key)
+	}
+	slices.Sort(writtenKeys)
+	for _, key := range writtenKeys {
+		if !c.declaresStorageKey(addr, []byte(key)) {
+			return out, ErrStorageKeyNotDeclared
+		}
+		previous, _, err := storage.GetContractStorage(c.ctx, mu, addr, []byte(key))
+		if err != nil {
+			return out, err
+		}
+		if err := updateStateDeposit(c.ctx, c.rules, mu, addr, c.payer, previous, res.StorageWrites[key]); err != nil {
+			return out, err
+		}
+		if err := storage.SetContractStorage(c.ctx, mu, addr, []byte(key), res.StorageWrites[key]); err != nil {
+			return out, err
+		}
+	}
+	if res.SelfDestruct != nil {
+		if metadata.Immutable {
+			return out, ErrContractImmutable
+		}
+		if !c.declaresRecipient(*res.SelfDestruct) {
+			return out, ErrRecipientNotDeclared
+		}
+		if err := deleteContract(c.ctx, c.rules, mu, addr, metadata, *res.SelfDestruct, c.storageKeys(addr)); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// nextSeed returns the tx ID for the first call of the tree and derives a
+// distinct seed for every nested one.
+func (c *contractCall) nextSeed() []byte {
+	seed := binary.BigEndian.AppendUint32(c.txID[:], c.calls)
+	if c.calls == 0 {
+		seed = c.txID[:]
+	}
+	c.calls++
+	return seed
+}
+
+// loadBytecode reads the bytecode of the contract at [addr], from the code
+// store if the contract was deployed from there and never upgraded.
+func (c *contractCall) loadBytecode(mu state.Immutable, addr codec.Address, metadata *storage.ContractMetadata) ([]byte, error) {
+	bytecode, err := storage.GetContractBytecode(c.ctx, mu, addr)
+	if !errors.Is(err, storage.ErrContractNotFound) {
+		return bytecode, err
+	}
+	if c.access != nil && !slices.Contains(c.access.Codes, metadata.CodeHash) {
+		return nil, ErrCodeNotDeclared
+	}
+	_, bytecode, err = storage.GetCode(c.ctx, mu, metadata.CodeHash)
+	return bytecode, err
+}
+
+func (c *contractCall) declaresCallee(addr codec.Address) bool {
+	if c.access == nil {
+		return true
+	}
+	for _, callee := range c.access.Callees {
+		if callee.Address == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *contractCall) declaresStateWrite(addr codec.Address) bool {
+	if c.access == nil {
+		return true
+	}
+	if addr == c.access.ContractAddress {
+		return c.access.WritesState
+	}
+	for _, callee := range c.access.Callees {
+		if callee.Address == addr {
+			return callee.WritesState
+		}
+	}
+	return false
+}
+
+func (c *contractCall) declaresStorageKey(addr codec.Address, key []byte) bool {
+	if c.access == nil {
+		return true
+	}
+	return slices.ContainsFunc(c.storageKeys(addr), func(declared []byte) bool {
+		return string(declared) == string(key)
+	})
+}
+
+// storageKeys returns the declared storage keys of the contract at [addr],
+// none for simulations.
+func (c *contractCall) storageKeys(addr codec.Address) [][]byte {
+	if c.access == nil {
+		return nil
+	}
+	if addr == c.access.ContractAddress {
+		return c.access.StorageKeys
+	}
+	for _, callee := range c.access.Callees {
+		if callee.Address == addr {
+			return callee.StorageKeys
+		}
+	}
+	return nil
+}
+
+// declaresRecipient allows transfers to the declared recipients and to the
+// contracts of the call.
+func (c *contractCall) declaresRecipient(addr codec.Address) bool {
+	if c.access == nil {
+		return true
+	}
+	return addr == c.access.ContractAddress ||
+		slices.Contains(c.access.Recipients, addr) ||
+		c.declaresCallee(addr)
+}
+
+// callChain serves the [execution.Chain] of the contract at [addr].
+type callChain struct {
+	call *contractCall
+	mu   state.Mutable
+	addr codec.Address
+
+	// events of the contract and its successful callees
+	events []Event
+}
+
+func (ch *callChain) Balance() (uint64, error) {
+	return storage.GetBalance(ch.call.ctx, ch.mu, ch.addr)
+}
+
+func (ch *callChain) Transfer(to codec.Address, amount uint64) error {
+	if !ch.call.declaresRecipient(to) {
+		return ErrRecipientNotDeclared
+	}
+	balance, err := ch.Balance()
+	if err != nil {
+		return err
+	}
+	if balance < amount {
+		return execution.ErrInsufficientFunds
+	}
+	if err := storage.SubBalance(ch.call.ctx, ch.mu, ch.addr, amount); err != nil {
+		return err
+	}
+	return storage.AddBalance(ch.call.ctx, ch.mu, to, amount, true)
+}
+
+// Call runs the callee on top of the state of the caller and only keeps its
+// changes if it succeeds.
+func (ch *callChain) Call(to codec.Address, input []byte, value uint64, maxFuel uint64) (*execution.CallResult, error) {
+	overlay := newStateOverlay(ch.mu)
+	events := ch.call.events
+	out, err := ch.call.run(overlay, ch.addr, to, input, value, maxFuel)
+	if out == nil || err != nil {
+		// the events of the callee are discarded with its changes
+		ch.call.events = events
+	}
+	if out == nil {
+		return nil, err
+	}
+	if err != nil {
+		return &execution.CallResult{Result: []byte(err.Error()), FuelConsumed: out.fuelConsumed}, nil
+	}
+	if err := overlay.Commit(ch.call.ctx); err != nil {
+		return nil, err
+	}
+	ch.events = append(ch.events, out.events...)
+	return &execution.CallResult{Success: true, Result: out.result, FuelConsumed: out.fuelConsumed}, nil
+}
+
+func (ch *callChain) Emit(topic []byte, data []byte) error {
+	if ch.call.events >= CallContractMaxEvents {
+		return ErrTooManyEvents
+	}
+	ch.call.events++
+	ch.events = append(ch.events, Event{Contract: ch.addr, Topic: topic, Data: data})
+	return nil
+}
+
+// contractStorage serves the declared storage keys of the contract at [addr].
+type contractStorage struct {
+	ctx  context.Context
+	im   state.Immutable
+	call *contractCall
+	addr codec.Address
+}
+
+func (s *contractStorage) Get(key []byte) ([]byte, bool, error) {
+	if !s.call.declaresStorageKey(s.addr, key) {
+		return nil, false, ErrStorageKeyNotDeclared
+	}
+	return storage.GetContractStorage(s.ctx, s.im, s.addr, key)
+}
+
+// SimulateCallContract executes a call on top of [im] without keeping any of
+// its changes. Every key may be accessed. It returns the output of the call,
+// the console output of the contract and the fuel consumed. The console output
+// and the fuel consumed of a failed call are returned with its error.
+func SimulateCallContract(
+	ctx context.Context,
+	rules ContractRules,
+	im state.Immutable,
+	timestamp int64,
+	actor codec.Address,
+	addr codec.Address,
+	payload []byte,
+	value uint64,
+) (*CallContractOutput, []byte, uint64, error) {
+	call := &contractCall{
+		ctx:       ctx,
+		rules:     rules,
+		timestamp: timestamp,
+		deadline:  time.Now().Add(contractMaxTime),
+		payer:     actor,
+	}
+	mu := newStateOverlay(&readOnlyState{im: im}) // never committed
+	out, err := call.run(mu, actor, addr, payload, value, rules.GetContractMaxFuel())
+	if err != nil {
+		if out == nil {
+			return nil, nil, 0, err
+		}
+		return nil, out.stdErr, out.fuelConsumed, err
+	}
+	return &CallContractOutput{Result: out.result, Events: out.events}, out.stdErr, out.fuelConsumed, nil
+}
+
+// DryRunCallContract executes [t] as [actor] on top of [im] without keeping
+// any of its changes. Unlike [SimulateCallContract], only the state declared
+// by [t] may be accessed, like on chain. It returns the fuel consumed.
+func DryRunCallContract(
+	ctx context.Context,
+	rules ContractRules,
+	im state.Immutable,
+	timestamp int64,
+	actor codec.Address,
+	t *CallContract,
+) (uint64, error) {
+	if t.MaxFuel > rules.GetContractMaxFuel() {
+		return 0, errors.New(string(OutputMaxFuelExceeded))
+	}
+	call := &contractCall{
+		ctx:       ctx,
+		rules:     rules,
+		timestamp: timestamp,
+		deadline:  time.Now().Add(contractMaxTime),
+		payer:     actor,
+		access:    t,
+	}
+	mu := newStateOverlay(&readOnlyState{im: im}) // never committed
+	out, err := call.run(mu, actor, t.ContractAddress, t.Payload, t.Value, t.MaxFuel)
+	if err != nil {
+		return 0, err
+	}
+	return out.fuelConsumed, nil
+}
+
+// ScreenCallContract reports whether this node executes [t] within its
+// wall-clock limit on top of [im]. Each call is only screened once, later
+// screenings pass.
+func ScreenCallContract(
+	ctx context.Context,
+	rules ContractRules,
+	im state.Immutable,
+	timestamp int64,
+	actor codec.Address,
+	t *CallContract,
+) bool {
+	if t.screened.Swap(true) {
+		return true
+	}
+	_, err := DryRunCallContract(ctx, rules, im, timestamp, actor, t)
+	return !errors.Is(err, execution.ErrTimeout)
+}
+
+var errReadOnlyState = errors.New("read-only state")
+
+// readOnlyState is the base of an overlay that is never committed.
+type readOnlyState struct {
+	im state.Immutable
+}
+
+func (s *readOnlyState) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	return s.im.GetValue(ctx, key)
+}
+
+func (*readOnlyState) Insert(context.Context, []byte, []byte) error {
+	return errReadOnlyState
+}
+
+func (*readOnlyState) Remove(context.Context, []byte) error {
+	return errReadOnlyState
+}
@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
@@ -18,12 +19,23 @@ import (
 	"github.com/containerman17/avalanche-polyglot-subnet/storage"
 )
 
+var ErrBytecodeTooLarge = errors.New("bytecode too large")
+
 var _ chain.Action = (*CreateContract)(nil)
 
 type CreateContract struct {
 	Bytecode      []byte
 	InitialState  []byte
 	Discriminator uint16
+
+	// EngineID selects the engine running the contract, it can't be changed
+	// later
+	EngineID uint8
+
+	// Admin may upgrade the contract in addition to the creator (optional)
+	Admin codec.Address
+	// Immutable disables upgrades and deletion permanently
+	Immutable bool
 }
 
 func (*CreateContract) GetTypeID() uint8 {
@@ -36,11 +48,14 @@ func (t *CreateContract) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
 	return state.Keys{
 		string(storage.ContractStateKey(contractAddress)):    state.All,
 		string(storage.ContractBytecodeKey(contractAddress)): state.All,
+		string(storage.ContractMetadataKey(contractAddress)): state.All,
+		string(storage.ContractDepositKey(contractAddress)):  state.All,
+		string(storage.BalanceKey(actor)):                    state.Read | state.Write,
 	}
 }
 
 func (*CreateContract) StateKeysMaxChunks() []uint16 {
-	return []uint16{storage.ContractStateChunks, storage.ContractBytecodeChunks}
+	return []uint16{storage.ContractStateChunks, storage.ContractBytecodeChunks, storage.ContractMetadataChunks, storage.ContractDepositChunks, storage.BalanceChunks}
 }
 
 func (*CreateContract) OutputsWarpMessage() bool {
@@ -49,30 +64,48 @@ func (*CreateContract) OutputsWarpMessage() bool {
 
 func (t *CreateContract) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
 	_ ids.ID,
 	_ bool,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := t.MaxComputeUnits(r)
+	if err := validateBytecode(r.(ContractRules), t.EngineID, t.Bytecode); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
 	addr, err := storage.CreateContract(ctx, mu, actor, t.Bytecode, t.InitialState, t.Discriminator)
 	if err != nil {
-		return false, 1, utils.ErrBytes(err), nil, nil
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if err := updateStateDeposit(ctx, r.(ContractRules), mu, addr, actor, nil, t.InitialState); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetContractMetadata(ctx, mu, addr, &storage.ContractMetadata{
+		Creator:   actor,
+		CodeHash:  utils.ToID(t.Bytecode),
+		CreatedAt: timestamp,
+		EngineID:  t.EngineID,
+		Admin:     t.Admin,
+		Immutable: t.Immutable,
+	}); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
 	}
 
 	addrString := codec.MustAddressBech32(mconsts.HRP, addr)
 
 	//success, computeUnits, output, warpMsg, err
-	return true, 1, []byte(addrString), nil, nil
+	return true, units, []byte(addrString), nil, nil
 }
 
-func (*CreateContract) MaxComputeUnits(chain.Rules) uint64 {
-	return 1
+func (t *CreateContract) MaxComputeUnits(r chain.Rules) uint64 {
+	return BytecodeComputeUnits(r.(ContractRules), t.Bytecode)
 }
 
 func (cc *CreateContract) Size() int {
-	return len(cc.Bytecode) + len(cc.InitialState) + consts.Uint8Len
+	return codec.BytesLen(cc.Bytecode) + codec.BytesLen(cc.InitialState) + codec.BytesLen(make([]byte, consts.Uint16Len)) +
+		consts.Uint8Len + codec.AddressLen + consts.BoolLen
 }
 
 func (t *CreateContract) Marshal(p *codec.Packer) {
@@ -82,6 +115,9 @@ func (t *CreateContract) Marshal(p *codec.Packer) {
 	discriminatorBytes := make([]byte, 2)
 	binary.BigEndian.PutUint16(discriminatorBytes, t.Discriminator)
 	p.PackBytes(discriminatorBytes)
+	p.PackByte(t.EngineID)
+	p.PackFixedBytes(t.Admin[:]) // may be empty
+	p.PackBool(t.Immutable)
 }
 
 func UnmarshalCreateContract(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
@@ -92,11 +128,53 @@ func UnmarshalCreateContract(p *codec.Packer, _ *warp.Message) (chain.Action, er
 	var discriminatorBytes []byte = make([]byte, 2)
 	p.UnpackBytes(2, false, &discriminatorBytes)
 	action.Discriminator = binary.BigEndian.Uint16(discriminatorBytes)
+	action.EngineID = p.UnpackByte()
 
-	return &action, nil
+	admin := make([]byte, codec.AddressLen)
+	p.UnpackFixedBytes(codec.AddressLen, &admin)
+	action.Admin = codec.Address(admin)
+	action.Immutable = p.UnpackBool()
+
+	return &action, p.Err()
 }
 
 func (*CreateContract) ValidRange(chain.Rules) (int64, int64) {
 	// Returning -1, -1 means that the action is always valid.
 	return -1, -1
 }
+
+// validateBytecode rejects the bytecode that can't be executed by the engine
+// [engineID], so it never gets deployed.
+func validateBytecode(r ContractRules, engineID uint8, bytecode []byte) error {
+	if uint64(len(bytecode)) > r.GetContractMaxBytecodeSize() {
+		return ErrBytecodeTooLarge
+	}
+	engine, err := mconsts.EngineRegistry.Get(engineID)
+	if err != nil {
+		return err
+	}
+	return engine.Validate(bytecode)
+}
+
+// updateStateDeposit locks the deposit of the contract at [addr] or returns
+// the deposit of the chunks freed for a value of its state replaced by
+// [newValue], see [storage.UpdateContractDeposit].
+func updateStateDeposit(
+	ctx context.Context,
+	r ContractRules,
+	mu state.Mutable,
+	addr codec.Address,
+	payer codec.Address,
+	oldValue []byte,
+	newValue []byte,
+) error {
+	return storage.UpdateContractDeposit(
+		ctx,
+		mu,
+		addr,
+		payer,
+		r.GetContractStorageDepositPerChunk(),
+		storage.StateChunks(oldValue),
+		storage.StateChunks(newValue),
+	)
+}
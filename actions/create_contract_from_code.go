@@ -0,0 +1,129 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var _ chain.Action = (*CreateContractFromCode)(nil)
+
+// CreateContractFromCode deploys a contract running code uploaded with
+// [UploadCode]. The bytecode is not copied, calls to the contract must
+// declare [CodeHash] in [CallContract.Codes].
+type CreateContractFromCode struct {
+	CodeHash      ids.ID `json:"codeHash"`
+	InitialState  []byte `json:"initialState"`
+	Discriminator uint16 `json:"discriminator"`
+
+	// Admin may upgrade the contract in addition to the creator (optional)
+	Admin codec.Address `json:"admin"`
+	// Immutable disables upgrades and deletion permanently
+	Immutable bool `json:"immutable"`
+}
+
+func (*CreateContractFromCode) GetTypeID() uint8 {
+	return mconsts.CreateContractFromCodeID
+}
+
+func (t *CreateContractFromCode) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	contractAddress := storage.GenerateContractAddress(actor, t.Discriminator)
+
+	return state.Keys{
+		string(storage.ContractStateKey(contractAddress)):    state.All,
+		string(storage.ContractMetadataKey(contractAddress)): state.All,
+		string(storage.CodeKey(t.CodeHash)):                  state.Read,
+		string(storage.ContractDepositKey(contractAddress)):  state.All,
+		string(storage.BalanceKey(actor)):                    state.Read | state.Write,
+	}
+}
+
+func (*CreateContractFromCode) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ContractStateChunks, storage.ContractMetadataChunks, storage.CodeChunks, storage.ContractDepositChunks, storage.BalanceChunks}
+}
+
+func (*CreateContractFromCode) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *CreateContractFromCode) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	// the code was validated when it was uploaded
+	engineID, _, err := storage.GetCode(ctx, mu, t.CodeHash)
+	if err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+	addr, err := storage.CreateContract(ctx, mu, actor, nil, t.InitialState, t.Discriminator)
+	if err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+	if err := updateStateDeposit(ctx, r.(ContractRules), mu, addr, actor, nil, t.InitialState); err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetContractMetadata(ctx, mu, addr, &storage.ContractMetadata{
+		Creator:   actor,
+		CodeHash:  t.CodeHash,
+		CreatedAt: timestamp,
+		EngineID:  engineID,
+		Admin:     t.Admin,
+		Immutable: t.Immutable,
+	}); err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, 1, []byte(codec.MustAddressBech32(mconsts.HRP, addr)), nil, nil
+}
+
+func (*CreateContractFromCode) MaxComputeUnits(chain.Rules) uint64 {
+	return 1
+}
+
+func (t *CreateContractFromCode) Size() int {
+	return consts.IDLen + codec.BytesLen(t.InitialState) + consts.Uint16Len + codec.AddressLen + consts.BoolLen
+}
+
+func (t *CreateContractFromCode) Marshal(p *codec.Packer) {
+	p.PackID(t.CodeHash)
+	p.PackBytes(t.InitialState)
+	p.PackFixedBytes(binary.BigEndian.AppendUint16(nil, t.Discriminator))
+	p.PackFixedBytes(t.Admin[:]) // may be empty
+	p.PackBool(t.Immutable)
+}
+
+func UnmarshalCreateContractFromCode(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var action CreateContractFromCode
+	p.UnpackID(true, &action.CodeHash)
+	p.UnpackBytes(-1, false, &action.InitialState)
+	discriminator := make([]byte, consts.Uint16Len)
+	p.UnpackFixedBytes(consts.Uint16Len, &discriminator)
+	action.Discriminator = binary.BigEndian.Uint16(discriminator)
+	admin := make([]byte, codec.AddressLen)
+	p.UnpackFixedBytes(codec.AddressLen, &admin)
+	action.Admin = codec.Address(admin)
+	action.Immutable = p.UnpackBool()
+	return &action, p.Err()
+}
+
+func (*CreateContractFromCode) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
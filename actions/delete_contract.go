@@ -0,0 +1,193 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var (
+	ErrNotContractCreator = errors.New("only the creator can delete the contract")
+	ErrInvalidBeneficiary = errors.New("the beneficiary can't be the deleted contract")
+)
+
+var _ chain.Action = (*DeleteContract)(nil)
+
+// DeleteContract removes a contract and sends its balance and storage deposit
+// to [Beneficiary].
+// Only the creator may delete a contract, contracts can also delete themselves
+// with the self_destruct host function. Immutable contracts can't be deleted
+// either way.
+type DeleteContract struct {
+	ContractAddress codec.Address `json:"contractAddress"`
+	Beneficiary     codec.Address `json:"beneficiary"`
+
+	// StorageKeys are the keys of the contract storage to clear, the others
+	// are left behind with their deposit locked until the creator deletes the
+	// contract again with them
+	StorageKeys [][]byte `json:"storageKeys"`
+}
+
+func (*DeleteContract) GetTypeID() uint8 {
+	return mconsts.DeleteContractID
+}
+
+func (t *DeleteContract) StateKeys(codec.Address, ids.ID) state.Keys {
+	keys := state.Keys{
+		string(storage.ContractMetadataKey(t.ContractAddress)): state.Read | state.Write,
+		string(storage.ContractBytecodeKey(t.ContractAddress)): state.Write,
+		string(storage.ContractStateKey(t.ContractAddress)):    state.Read | state.Write,
+		string(storage.BalanceKey(t.ContractAddress)):          state.All,
+		string(storage.ContractDepositKey(t.ContractAddress)):  state.Read | state.Write,
+		string(storage.BalanceKey(t.Beneficiary)):              state.All,
+	}
+	for _, key := range t.StorageKeys {
+		keys.Add(string(storage.ContractStorageKey(t.ContractAddress, key)), state.Read|state.Write)
+	}
+	return keys
+}
+
+func (t *DeleteContract) StateKeysMaxChunks() []uint16 {
+	chunks := []uint16{
+		storage.ContractMetadataChunks,
+		storage.ContractBytecodeChunks,
+		storage.ContractStateChunks,
+		storage.BalanceChunks,
+		storage.ContractDepositChunks,
+		storage.BalanceChunks,
+	}
+	for range t.StorageKeys {
+		chunks = append(chunks, storage.ContractStorageChunks)
+	}
+	return chunks
+}
+
+func (*DeleteContract) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *DeleteContract) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	// a deleted contract is deleted again to clear the storage it left behind
+	metadata, err := storage.GetContractMetadataOrTombstone(ctx, mu, t.ContractAddress)
+	if err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+	if actor != metadata.Creator {
+		return false, 1, utils.ErrBytes(ErrNotContractCreator), nil, nil
+	}
+	if metadata.Immutable {
+		return false, 1, utils.ErrBytes(ErrContractImmutable), nil, nil
+	}
+	if err := deleteContract(ctx, r.(ContractRules), mu, t.ContractAddress, metadata, t.Beneficiary, t.StorageKeys); err != nil {
+		return false, 1, utils.ErrBytes(err), nil, nil
+	}
+	return true, 1, nil, nil, nil
+}
+
+// deleteContract removes the contract at [addr] with [storageKeys] and sweeps
+// its balance, with the deposit of the chunks freed, to [beneficiary].
+func deleteContract(
+	ctx context.Context,
+	r ContractRules,
+	mu state.Mutable,
+	addr codec.Address,
+	metadata *storage.ContractMetadata,
+	beneficiary codec.Address,
+	storageKeys [][]byte,
+) error {
+	if beneficiary == addr {
+		return ErrInvalidBeneficiary
+	}
+	var contractState []byte
+	if !metadata.Deleted {
+		var err error
+		contractState, err = storage.GetContractState(ctx, mu, addr)
+		if err != nil {
+			return err
+		}
+	}
+	if err := updateStateDeposit(ctx, r, mu, addr, beneficiary, contractState, nil); err != nil {
+		return err
+	}
+	for _, key := range storageKeys {
+		value, _, err := storage.GetContractStorage(ctx, mu, addr, key)
+		if err != nil {
+			return err
+		}
+		if err := updateStateDeposit(ctx, r, mu, addr, beneficiary, value, nil); err != nil {
+			return err
+		}
+		if err := storage.SetContractStorage(ctx, mu, addr, key, nil); err != nil {
+			return err
+		}
+	}
+	if err := storage.DeleteContract(ctx, mu, addr, metadata); err != nil {
+		return err
+	}
+	balance, err := storage.GetBalance(ctx, mu, addr)
+	if err != nil || balance == 0 {
+		return err
+	}
+	if err := storage.SubBalance(ctx, mu, addr, balance); err != nil {
+		return err
+	}
+	return storage.AddBalance(ctx, mu, beneficiary, balance, true)
+}
+
+func (*DeleteContract) MaxComputeUnits(chain.Rules) uint64 {
+	return 1
+}
+
+func (t *DeleteContract) Size() int {
+	size := codec.AddressLen*2 + consts.IntLen
+	for _, key := range t.StorageKeys {
+		size += codec.BytesLen(key)
+	}
+	return size
+}
+
+func (t *DeleteContract) Marshal(p *codec.Packer) {
+	p.PackAddress(t.ContractAddress)
+	p.PackAddress(t.Beneficiary)
+	packStorageKeys(p, t.StorageKeys)
+}
+
+func UnmarshalDeleteContract(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var action DeleteContract
+	p.UnpackAddress(&action.ContractAddress)
+	p.UnpackAddress(&action.Beneficiary)
+	storageKeys, err := unpackStorageKeys(p)
+	if err != nil {
+		return nil, err
+	}
+	action.StorageKeys = storageKeys
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (*DeleteContract) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
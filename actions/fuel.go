@@ -0,0 +1,41 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import "github.com/ava-labs/hypersdk/chain"
+
+// ContractRules are the contract execution parameters provided by
+// [genesis.Rules] on top of [chain.Rules].
+type ContractRules interface {
+	chain.Rules
+
+	GetContractFuelPerComputeUnit() uint64
+	GetContractMaxFuel() uint64
+	GetContractMaxCallDepth() uint8 // nested calls, the called contract is at depth 0
+	GetContractMaxBytecodeSize() uint64
+	GetContractBytecodeBytesPerComputeUnit() uint64
+	GetContractStorageDepositPerChunk() uint64
+}
+
+// FuelComputeUnits converts [fuel] into compute units. Partial units are
+// rounded up, so any fuel consumed is paid for.
+func FuelComputeUnits(r ContractRules, fuel uint64) uint64 {
+	fuelPerUnit := r.GetContractFuelPerComputeUnit()
+	units := fuel / fuelPerUnit
+	if fuel%fuelPerUnit != 0 {
+		units++
+	}
+	return units
+}
+
+// BytecodeComputeUnits is charged by the actions validating [bytecode], which
+// compiles it. Partial units are rounded up.
+func BytecodeComputeUnits(r ContractRules, bytecode []byte) uint64 {
+	bytesPerUnit := r.GetContractBytecodeBytesPerComputeUnit()
+	units := uint64(len(bytecode)) / bytesPerUnit
+	if uint64(len(bytecode))%bytesPerUnit != 0 {
+		units++
+	}
+	return 1 + units
+}
@@ -3,4 +3,7 @@
 
 package actions
 
-var OutputValueZero = []byte("value is zero")
+var (
+	OutputValueZero       = []byte("value is zero")
+	OutputMaxFuelExceeded = []byte("max fuel exceeds limit")
+)
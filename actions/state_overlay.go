@@ -0,0 +1,69 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ state.Mutable = (*stateOverlay)(nil)
+
+// stateOverlay buffers changes on top of [parent] until they are committed,
+// so a nested call can be discarded without affecting its caller.
+type stateOverlay struct {
+	parent  state.Mutable
+	changes map[string]maybe.Maybe[[]byte]
+}
+
+func newStateOverlay(parent state.Mutable) *stateOverlay {
+	return &stateOverlay{parent: parent, changes: map[string]maybe.Maybe[[]byte]{}}
+}
+
+func (s *stateOverlay) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	if v, ok := s.changes[string(key)]; ok {
+		if v.IsNothing() {
+			return nil, database.ErrNotFound
+		}
+		return v.Value(), nil
+	}
+	return s.parent.GetValue(ctx, key)
+}
+
+func (s *stateOverlay) Insert(_ context.Context, key []byte, value []byte) error {
+	s.changes[string(key)] = maybe.Some(value)
+	return nil
+}
+
+func (s *stateOverlay) Remove(_ context.Context, key []byte) error {
+	s.changes[string(key)] = maybe.Nothing[[]byte]()
+	return nil
+}
+
+// Commit applies the changes to the parent in key order, so every node
+// performs the same operations.
+func (s *stateOverlay) Commit(ctx context.Context) error {
+	keys := make([]string, 0, len(s.changes))
+	for key := range s.changes {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		v := s.changes[key]
+		var err error
+		if v.IsNothing() {
+			err = s.parent.Remove(ctx, []byte(key))
+		} else {
+			err = s.parent.Insert(ctx, []byte(key), v.Value())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var (
+	ErrContractImmutable = errors.New("contract is immutable")
+	ErrNotContractAdmin  = errors.New("only the creator or the admin can upgrade the contract")
+)
+
+var _ chain.Action = (*UpgradeContract)(nil)
+
+// UpgradeContract replaces the bytecode of a contract and keeps its state,
+// balance, storage and engine.
+type UpgradeContract struct {
+	ContractAddress codec.Address
+	Bytecode        []byte
+}
+
+func (*UpgradeContract) GetTypeID() uint8 {
+	return mconsts.UpgradeContractID
+}
+
+func (t *UpgradeContract) StateKeys(codec.Address, ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.ContractMetadataKey(t.ContractAddress)): state.Read | state.Write,
+		// contracts deployed from the code store have no bytecode until
+		// their first upgrade
+		string(storage.ContractBytecodeKey(t.ContractAddress)): state.All,
+	}
+}
+
+func (*UpgradeContract) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ContractMetadataChunks, storage.ContractBytecodeChunks}
+}
+
+func (*UpgradeContract) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *UpgradeContract) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := t.MaxComputeUnits(r)
+	metadata, err := storage.GetContractMetadata(ctx, mu, t.ContractAddress)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if metadata.Immutable {
+		return false, units, utils.ErrBytes(ErrContractImmutable), nil, nil
+	}
+	if !metadata.CanUpgrade(actor) {
+		return false, units, utils.ErrBytes(ErrNotContractAdmin), nil, nil
+	}
+	if err := validateBytecode(r.(ContractRules), metadata.EngineID, t.Bytecode); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetContractBytecode(ctx, mu, t.ContractAddress, t.Bytecode); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	metadata.CodeHash = utils.ToID(t.Bytecode)
+	if err := storage.SetContractMetadata(ctx, mu, t.ContractAddress, metadata); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	return true, units, nil, nil, nil
+}
+
+func (t *UpgradeContract) MaxComputeUnits(r chain.Rules) uint64 {
+	return BytecodeComputeUnits(r.(ContractRules), t.Bytecode)
+}
+
+func (t *UpgradeContract) Size() int {
+	return codec.AddressLen + codec.BytesLen(t.Bytecode)
+}
+
+func (t *UpgradeContract) Marshal(p *codec.Packer) {
+	p.PackAddress(t.ContractAddress)
+	p.PackBytes(t.Bytecode)
+}
+
+func UnmarshalUpgradeContract(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var action UpgradeContract
+	p.UnpackAddress(&action.ContractAddress)
+	p.UnpackBytes(-1, true, &action.Bytecode)
+	return &action, p.Err()
+}
+
+func (*UpgradeContract) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
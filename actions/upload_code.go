@@ -0,0 +1,103 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	mconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+)
+
+var ErrCodeExists = errors.New("code already uploaded")
+
+var _ chain.Action = (*UploadCode)(nil)
+
+// UploadCode stores bytecode in the code store, keyed by its SHA-256 hash, so
+// any number of contracts can be deployed from it with
+// [CreateContractFromCode].
+type UploadCode struct {
+	// EngineID selects the engine running the contracts deployed from the
+	// code
+	EngineID uint8  `json:"engineID"`
+	Bytecode []byte `json:"bytecode"`
+}
+
+func (*UploadCode) GetTypeID() uint8 {
+	return mconsts.UploadCodeID
+}
+
+func (t *UploadCode) StateKeys(codec.Address, ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.CodeKey(utils.ToID(t.Bytecode))): state.All,
+	}
+}
+
+func (*UploadCode) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.CodeChunks}
+}
+
+func (*UploadCode) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *UploadCode) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := t.MaxComputeUnits(r)
+	if err := validateBytecode(r.(ContractRules), t.EngineID, t.Bytecode); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	hash := utils.ToID(t.Bytecode)
+	_, _, err := storage.GetCode(ctx, mu, hash)
+	if err == nil {
+		return false, units, utils.ErrBytes(ErrCodeExists), nil, nil
+	}
+	if !errors.Is(err, storage.ErrCodeNotFound) {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetCode(ctx, mu, hash, t.EngineID, t.Bytecode); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	return true, units, []byte(hash.String()), nil, nil
+}
+
+func (t *UploadCode) MaxComputeUnits(r chain.Rules) uint64 {
+	return BytecodeComputeUnits(r.(ContractRules), t.Bytecode)
+}
+
+func (t *UploadCode) Size() int {
+	return consts.Uint8Len + codec.BytesLen(t.Bytecode)
+}
+
+func (t *UploadCode) Marshal(p *codec.Packer) {
+	p.PackByte(t.EngineID)
+	p.PackBytes(t.Bytecode)
+}
+
+func UnmarshalUploadCode(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var action UploadCode
+	action.EngineID = p.UnpackByte()
+	p.UnpackBytes(-1, true, &action.Bytecode)
+	return &action, p.Err()
+}
+
+func (*UploadCode) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -5,9 +5,14 @@ package cmd
 
 import (
 	"context"
+	"math"
+	"os"
 
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
 	"github.com/containerman17/avalanche-polyglot-subnet/actions"
 	"github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -59,3 +64,86 @@ var transferCmd = &cobra.Command{
 		return err
 	},
 }
+
+var createContractCmd = &cobra.Command{
+	Use: "create-contract [wasm file] [initial state file]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return ErrInvalidArgs
+		}
+		if discriminator > math.MaxUint16 {
+			return ErrInvalidDiscriminator
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, bcli, ws, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Read contract
+		bytecode, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		initialState := []byte{}
+		if len(args) == 2 {
+			initialState, err = os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+		}
+		utils.Outf(
+			"{{yellow}}bytecode:{{/}} %d bytes {{yellow}}initial state:{{/}} %d bytes\n",
+			len(bytecode),
+			len(initialState),
+		)
+
+		// Select discriminator
+		d := discriminator
+		if d < 0 {
+			v, err := handler.Root().PromptInt("discriminator", math.MaxUint16)
+			if err != nil {
+				return err
+			}
+			d = int64(v)
+		}
+		predicted := storage.GenerateContractAddress(priv.Address, uint16(d))
+		utils.Outf("{{yellow}}contract address:{{/}} %s\n", codec.MustAddressBech32(consts.HRP, predicted))
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		action := &actions.CreateContract{
+			Bytecode:      bytecode,
+			InitialState:  initialState,
+			Discriminator: uint16(d),
+			EngineID:      engineID,
+		}
+		result, _, err := sendAndWait(ctx, nil, action, cli, bcli, ws, factory, true)
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			utils.Outf("{{red}}error:{{/}} %s\n", string(result.Output))
+			return ErrDeployFailed
+		}
+
+		// Print created contract
+		contractAddr, err := codec.ParseAddressBech32(consts.HRP, string(result.Output))
+		if err != nil {
+			return err
+		}
+		if contractAddr != predicted {
+			utils.Outf("{{red}}contract address differs from the prediction{{/}}\n")
+		}
+		utils.Outf("{{green}}created contract:{{/}} %s\n", codec.MustAddressBech32(consts.HRP, contractAddr))
+		return nil
+	},
+}
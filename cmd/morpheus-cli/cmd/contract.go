@@ -0,0 +1,495 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/containerman17/avalanche-polyglot-subnet/abi"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	"github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rawEncoding    = "raw"
+	hexEncoding    = "hex"
+	base64Encoding = "base64"
+)
+
+var contractCmd = &cobra.Command{
+	Use: "contract",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+func checkContractArgs(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return ErrInvalidArgs
+	}
+	if _, err := codec.ParseAddressBech32(consts.HRP, args[0]); err != nil {
+		return err
+	}
+	return checkEncoding(bytesEncoding)
+}
+
+func checkEncoding(encoding string) error {
+	switch encoding {
+	case rawEncoding, hexEncoding, base64Encoding:
+		return nil
+	default:
+		return ErrInvalidEncoding
+	}
+}
+
+func encodeBytes(encoding string, b []byte) []byte {
+	switch encoding {
+	case hexEncoding:
+		return []byte(hex.EncodeToString(b) + "\n")
+	case base64Encoding:
+		return []byte(base64.StdEncoding.EncodeToString(b) + "\n")
+	default:
+		return b
+	}
+}
+
+func decodeBytes(encoding string, b []byte) ([]byte, error) {
+	switch encoding {
+	case hexEncoding:
+		return hex.DecodeString(strings.TrimSpace(string(b)))
+	case base64Encoding:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	default:
+		return b, nil
+	}
+}
+
+// writeBytes writes [b] to [bytesOutput], or to stdout if it is empty
+func writeBytes(b []byte) error {
+	b = encodeBytes(bytesEncoding, b)
+	if len(bytesOutput) == 0 {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	if err := os.WriteFile(bytesOutput, b, fsModeWrite); err != nil {
+		return err
+	}
+	utils.Outf("{{green}}wrote %d bytes to:{{/}} %s\n", len(b), bytesOutput)
+	return nil
+}
+
+var infoContractCmd = &cobra.Command{
+	Use:     "info [address]",
+	PreRunE: checkContractArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		cli, err := handler.DefaultClient()
+		if err != nil {
+			return err
+		}
+		bytecode, err := cli.ContractBytecode(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if len(bytecode) == 0 {
+			utils.Outf("{{yellow}}exists:{{/}} false\n")
+			return nil
+		}
+		info, err := cli.ContractInfo(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		state, err := cli.ContractState(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		deposit, _, err := cli.ContractDeposit(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		balance, err := cli.Balance(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}exists:{{/}} true\n")
+		utils.Outf("{{yellow}}code hash:{{/}} %s\n", info.CodeHash)
+		utils.Outf("{{yellow}}bytecode size:{{/}} %d bytes\n", len(bytecode))
+		utils.Outf("{{yellow}}state size:{{/}} %d bytes\n", len(state))
+		utils.Outf("{{yellow}}engine:{{/}} %d\n", info.EngineID)
+		utils.Outf("{{yellow}}creator:{{/}} %s\n", info.Creator)
+		if len(info.Admin) > 0 {
+			utils.Outf("{{yellow}}admin:{{/}} %s\n", info.Admin)
+		}
+		utils.Outf("{{yellow}}immutable:{{/}} %t\n", info.Immutable)
+		utils.Outf("{{yellow}}created at:{{/}} %d\n", info.CreatedAt)
+		utils.Outf(
+			"{{yellow}}balance:{{/}} %s %s {{yellow}}storage deposit:{{/}} %s %s\n",
+			utils.FormatBalance(balance, consts.Decimals),
+			consts.Symbol,
+			utils.FormatBalance(deposit, consts.Decimals),
+			consts.Symbol,
+		)
+		return nil
+	},
+}
+
+var stateContractCmd = &cobra.Command{
+	Use:     "state [address]",
+	PreRunE: checkContractArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		cli, err := handler.DefaultClient()
+		if err != nil {
+			return err
+		}
+		contractABI, err := loadABI()
+		if err != nil {
+			return err
+		}
+		state, err := cli.ContractState(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		if contractABI != nil {
+			// written as JSON, whatever the encoding
+			decoded, err := contractABI.DecodeState(state)
+			if err != nil {
+				return err
+			}
+			bytesEncoding = rawEncoding
+			return writeBytes(append(decoded, '\n'))
+		}
+		return writeBytes(state)
+	},
+}
+
+var bytecodeContractCmd = &cobra.Command{
+	Use:     "bytecode [address]",
+	PreRunE: checkContractArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		cli, err := handler.DefaultClient()
+		if err != nil {
+			return err
+		}
+		bytecode, err := cli.ContractBytecode(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		return writeBytes(bytecode)
+	},
+}
+
+// readSnapshot reads a state snapshot from the file at [arg], or fetches the
+// current state if [arg] is a contract address.
+func readSnapshot(ctx context.Context, arg string) ([]byte, error) {
+	if _, err := codec.ParseAddressBech32(consts.HRP, arg); err == nil {
+		cli, err := handler.DefaultClient()
+		if err != nil {
+			return nil, err
+		}
+		return cli.ContractState(ctx, arg)
+	}
+	b, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBytes(bytesEncoding, b)
+}
+
+// byteRange is a run of differing bytes starting at [start] of the old
+// snapshot
+type byteRange struct {
+	start  int
+	before []byte
+	after  []byte
+}
+
+// diffBytes returns the runs of bytes that differ between [a] and [b]
+func diffBytes(a []byte, b []byte) []byteRange {
+	ranges := []byteRange{}
+	start := -1
+	for i := 0; i <= max(len(a), len(b)); i++ {
+		differs := i < max(len(a), len(b)) && (i >= len(a) || i >= len(b) || a[i] != b[i])
+		switch {
+		case differs && start < 0:
+			start = i
+		case !differs && start >= 0:
+			ranges = append(ranges, byteRange{
+				start:  start,
+				before: a[min(start, len(a)):min(i, len(a))],
+				after:  b[min(start, len(b)):min(i, len(b))],
+			})
+			start = -1
+		}
+	}
+	return ranges
+}
+
+var diffContractCmd = &cobra.Command{
+	Use: "diff [old snapshot/address] [new snapshot/address]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return checkEncoding(bytesEncoding)
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		oldState, err := readSnapshot(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		newState, err := readSnapshot(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(oldState, newState) {
+			utils.Outf("{{green}}no changes{{/}} (%d bytes)\n", len(oldState))
+			return nil
+		}
+		utils.Outf("{{yellow}}size:{{/}} %d -> %d bytes\n", len(oldState), len(newState))
+		for _, r := range diffBytes(oldState, newState) {
+			utils.Outf(
+				"{{yellow}}offset %d:{{/}} {{red}}%x{{/}} -> {{green}}%x{{/}}\n",
+				r.start,
+				r.before,
+				r.after,
+			)
+		}
+		return nil
+	},
+}
+
+// loadABI returns the ABI at [abiFile], nil if it is empty
+func loadABI() (*abi.ABI, error) {
+	if len(abiFile) == 0 {
+		return nil, nil
+	}
+	b, err := os.ReadFile(abiFile)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Parse(b)
+}
+
+// readPayload returns the payload of a local run from the flags, they are
+// exclusive. A call is encoded with [contractABI].
+func readPayload(contractABI *abi.ABI) ([]byte, error) {
+	set := 0
+	for _, flag := range []string{runLocalPayload, runLocalPayloadFile, runLocalCall} {
+		if len(flag) > 0 {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, ErrInvalidPayload
+	}
+	switch {
+	case len(runLocalPayloadFile) > 0:
+		return os.ReadFile(runLocalPayloadFile)
+	case len(runLocalCall) > 0:
+		if contractABI == nil {
+			return nil, ErrMissingABI
+		}
+		return contractABI.EncodeCall(runLocalCall, []byte(runLocalArgs))
+	default:
+		return hex.DecodeString(strings.TrimPrefix(runLocalPayload, "0x"))
+	}
+}
+
+// localStorage serves the storage of a local run from memory
+type localStorage map[string][]byte
+
+func (s localStorage) Get(key []byte) ([]byte, bool, error) {
+	value, ok := s[string(key)]
+	return value, ok, nil
+}
+
+// readLocalStorage loads the storage file at [path], a JSON object of hex
+// keys to hex values. The storage is empty if [path] is empty or missing.
+func readLocalStorage(path string) (localStorage, error) {
+	storage := localStorage{}
+	if len(path) == 0 {
+		return storage, nil
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return storage, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	for k, v := range values {
+		key, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		storage[string(key)] = value
+	}
+	return storage, nil
+}
+
+// writeLocalStorage applies [writes] to [storage] and saves it to [path], an
+// empty value removes the key.
+func writeLocalStorage(path string, storage localStorage, writes map[string][]byte) error {
+	for k, v := range writes {
+		if len(v) == 0 {
+			delete(storage, k)
+			continue
+		}
+		storage[k] = v
+	}
+	values := make(map[string]string, len(storage))
+	for k, v := range storage {
+		values[hex.EncodeToString([]byte(k))] = hex.EncodeToString(v)
+	}
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), fsModeWrite)
+}
+
+var runLocalContractCmd = &cobra.Command{
+	Use: "run-local [wasm file]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		if runLocalWriteState && len(runLocalState) == 0 && len(runLocalStorage) == 0 {
+			return ErrMissingStateFile
+		}
+		return checkEncoding(bytesEncoding)
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		bytecode, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		state := []byte{}
+		if len(runLocalState) > 0 {
+			b, err := os.ReadFile(runLocalState)
+			switch {
+			case err == nil:
+				state, err = decodeBytes(bytesEncoding, b)
+				if err != nil {
+					return err
+				}
+			case !os.IsNotExist(err):
+				return err
+			}
+		}
+		contractStorage, err := readLocalStorage(runLocalStorage)
+		if err != nil {
+			return err
+		}
+		contractABI, err := loadABI()
+		if err != nil {
+			return err
+		}
+		payload, err := readPayload(contractABI)
+		if err != nil {
+			return err
+		}
+		actor := codec.EmptyAddress
+		if len(runLocalActor) > 0 {
+			actor, err = codec.ParseAddressBech32(consts.HRP, runLocalActor)
+			if err != nil {
+				return err
+			}
+		}
+
+		engine, err := consts.EngineRegistry.Get(engineID)
+		if err != nil {
+			return err
+		}
+
+		// Execute offline, the contract has no chain access
+		res, err := engine.Execute(execution.ExecParams{
+			MaxFuel:      runLocalMaxFuel,
+			MaxTime:      runLocalMaxTime,
+			MaxMemory:    runLocalMaxMemory,
+			MaxOutput:    actions.ContractMaxOutput,
+			Timestamp:    time.Now().UnixMilli(),
+			Bytecode:     &bytecode,
+			Storage:      contractStorage,
+			CurrentState: state,
+			Payload:      payload,
+			Actor:        actor[:],
+		})
+		if err != nil {
+			// failed runs still report their console and fuel
+			if res != nil {
+				utils.Outf("{{yellow}}fuel consumed:{{/}} %d\n", res.FuelConsumed)
+				if len(res.StdErr) > 0 {
+					utils.Outf("{{yellow}}console:{{/}}\n%s\n", res.StdErr)
+				}
+			}
+			return err
+		}
+		utils.Outf("{{yellow}}fuel consumed:{{/}} %d {{yellow}}time taken:{{/}} %s\n", res.FuelConsumed, res.TimeTaken)
+		utils.Outf("{{yellow}}result:{{/}} %x\n", res.Result)
+		if contractABI != nil && len(runLocalCall) > 0 {
+			if decoded, err := contractABI.DecodeResult(runLocalCall, res.Result); err == nil {
+				utils.Outf("{{yellow}}decoded result:{{/}} %s\n", decoded)
+			}
+		}
+		if len(res.StdErr) > 0 {
+			utils.Outf("{{yellow}}console:{{/}}\n%s\n", res.StdErr)
+		}
+		keys := make([]string, 0, len(res.StorageWrites))
+		for k := range res.StorageWrites {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			utils.Outf("{{yellow}}storage write:{{/}} %x -> %x\n", k, res.StorageWrites[k])
+		}
+		if runLocalWriteState && len(runLocalStorage) > 0 && len(res.StorageWrites) > 0 {
+			if err := writeLocalStorage(runLocalStorage, contractStorage, res.StorageWrites); err != nil {
+				return err
+			}
+			utils.Outf("{{green}}wrote storage to:{{/}} %s\n", runLocalStorage)
+		}
+		if res.UpdatedState == nil {
+			utils.Outf("{{yellow}}state:{{/}} unchanged\n")
+			return nil
+		}
+		utils.Outf("{{yellow}}state (%d bytes):{{/}} %x\n", len(*res.UpdatedState), *res.UpdatedState)
+		if contractABI != nil && contractABI.State != nil {
+			decoded, err := contractABI.DecodeState(*res.UpdatedState)
+			if err != nil {
+				return err
+			}
+			utils.Outf("{{yellow}}decoded state:{{/}} %s\n", decoded)
+		}
+		if !runLocalWriteState || len(runLocalState) == 0 {
+			return nil
+		}
+		if err := os.WriteFile(runLocalState, encodeBytes(bytesEncoding, *res.UpdatedState), fsModeWrite); err != nil {
+			return err
+		}
+		utils.Outf("{{green}}wrote state to:{{/}} %s\n", runLocalState)
+		return nil
+	},
+}
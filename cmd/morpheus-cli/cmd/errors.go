@@ -10,4 +10,11 @@ var (
 	ErrMissingSubcommand = errors.New("must specify a subcommand")
 	ErrInvalidAddress    = errors.New("invalid address")
 	ErrInvalidKeyType    = errors.New("invalid key type")
+
+	ErrInvalidDiscriminator = errors.New("discriminator must be <= 65535")
+	ErrInvalidEncoding      = errors.New("encoding must be raw, hex or base64")
+	ErrInvalidPayload       = errors.New("payload, payload file and call are exclusive")
+	ErrMissingABI           = errors.New("encoding a call requires an abi")
+	ErrMissingStateFile     = errors.New("writing the state requires a state or storage file")
+	ErrDeployFailed         = errors.New("contract deployment failed")
 )
@@ -83,6 +83,20 @@ func (h *Handler) DefaultActor() (
 		), ws, nil
 }
 
+// DefaultClient returns a client for the first URI of the default chain, it
+// doesn't require a default key.
+func (h *Handler) DefaultClient() (*brpc.JSONRPCClient, error) {
+	chainID, uris, err := h.h.GetDefaultChain(true)
+	if err != nil {
+		return nil, err
+	}
+	networkID, _, _, err := rpc.NewJSONRPCClient(uris[0]).Network(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return brpc.NewJSONRPCClient(uris[0], networkID, chainID), nil
+}
+
 func (*Handler) GetBalance(
 	ctx context.Context,
 	cli *brpc.JSONRPCClient,
@@ -20,30 +20,31 @@ import (
 	brpc "github.com/containerman17/avalanche-polyglot-subnet/rpc"
 )
 
-// sendAndWait may not be used concurrently
+// sendAndWait may not be used concurrently, it returns the result of the
+// transaction once accepted
 func sendAndWait(
 	ctx context.Context, warpMsg *warp.Message, action chain.Action, cli *rpc.JSONRPCClient,
 	bcli *brpc.JSONRPCClient, ws *rpc.WebSocketClient, factory chain.AuthFactory, printStatus bool,
-) (bool, ids.ID, error) { //nolint:unparam
+) (*chain.Result, ids.ID, error) { //nolint:unparam
 	parser, err := bcli.Parser(ctx)
 	if err != nil {
-		return false, ids.Empty, err
+		return nil, ids.Empty, err
 	}
 	_, tx, _, err := cli.GenerateTransaction(ctx, parser, warpMsg, action, factory)
 	if err != nil {
-		return false, ids.Empty, err
+		return nil, ids.Empty, err
 	}
 	if err := ws.RegisterTx(tx); err != nil {
-		return false, ids.Empty, err
+		return nil, ids.Empty, err
 	}
 	var result *chain.Result
 	for {
 		txID, txErr, txResult, err := ws.ListenTx(ctx)
 		if err != nil {
-			return false, ids.Empty, err
+			return nil, ids.Empty, err
 		}
 		if txErr != nil {
-			return false, ids.Empty, txErr
+			return nil, ids.Empty, txErr
 		}
 		if txID == tx.ID() {
 			result = txResult
@@ -54,7 +55,7 @@ func sendAndWait(
 	if printStatus {
 		handler.Root().PrintStatus(tx.ID(), result.Success)
 	}
-	return result.Success, tx.ID(), nil
+	return result, tx.ID(), nil
 }
 
 func handleTx(tx *chain.Transaction, result *chain.Result) {
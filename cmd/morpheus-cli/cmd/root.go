@@ -9,6 +9,9 @@ import (
 
 	"github.com/ava-labs/hypersdk/cli"
 	"github.com/ava-labs/hypersdk/utils"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +40,22 @@ var (
 	prometheusData        string
 	startPrometheus       bool
 	maxFee                int64
+	discriminator         int64
+	engineID              uint8
+	bytesEncoding         string
+	bytesOutput           string
+	runLocalState         string
+	runLocalStorage       string
+	runLocalPayload       string
+	runLocalPayloadFile   string
+	runLocalActor         string
+	runLocalMaxFuel       uint64
+	runLocalMaxMemory     int64
+	runLocalMaxTime       time.Duration
+	runLocalWriteState    bool
+	runLocalCall          string
+	runLocalArgs          string
+	abiFile               string
 
 	rootCmd = &cobra.Command{
 		Use:        "morpheus-cli",
@@ -52,6 +71,7 @@ func init() {
 		keyCmd,
 		chainCmd,
 		actionCmd,
+		contractCmd,
 		spamCmd,
 		prometheusCmd,
 	)
@@ -142,8 +162,136 @@ func init() {
 	)
 
 	// actions
+	createContractCmd.PersistentFlags().Int64Var(
+		&discriminator,
+		"discriminator",
+		-1,
+		"discriminator of the contract address (prompted if negative)",
+	)
+	createContractCmd.PersistentFlags().Uint8Var(
+		&engineID,
+		"engine-id",
+		v1javy.EngineID,
+		"engine running the contract",
+	)
 	actionCmd.AddCommand(
 		transferCmd,
+		createContractCmd,
+	)
+
+	// contract
+	for _, c := range []*cobra.Command{stateContractCmd, bytecodeContractCmd} {
+		c.PersistentFlags().StringVar(
+			&bytesEncoding,
+			"encoding",
+			rawEncoding,
+			"output encoding (raw, hex or base64)",
+		)
+		c.PersistentFlags().StringVar(
+			&bytesOutput,
+			"output",
+			"",
+			"file to write to (stdout if empty)",
+		)
+	}
+	diffContractCmd.PersistentFlags().StringVar(
+		&bytesEncoding,
+		"encoding",
+		rawEncoding,
+		"encoding of the snapshot files (raw, hex or base64)",
+	)
+	runLocalContractCmd.PersistentFlags().Uint8Var(
+		&engineID,
+		"engine-id",
+		v1javy.EngineID,
+		"engine running the contract",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalState,
+		"state",
+		"",
+		"state file (empty state if missing)",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalStorage,
+		"storage",
+		"",
+		"storage file, a JSON object of hex keys to hex values (empty storage if missing)",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&bytesEncoding,
+		"encoding",
+		rawEncoding,
+		"encoding of the state file (raw, hex or base64)",
+	)
+	runLocalContractCmd.PersistentFlags().BoolVar(
+		&runLocalWriteState,
+		"write-state",
+		false,
+		"write the updated state and storage back to their files",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalPayload,
+		"payload",
+		"",
+		"hex encoded payload",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalPayloadFile,
+		"payload-file",
+		"",
+		"file holding the raw payload",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalCall,
+		"call",
+		"",
+		"call of the abi to encode as the payload",
+	)
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalArgs,
+		"args",
+		"{}",
+		"JSON object holding the args of the call",
+	)
+	for _, c := range []*cobra.Command{stateContractCmd, runLocalContractCmd} {
+		c.PersistentFlags().StringVar(
+			&abiFile,
+			"abi",
+			"",
+			"JSON abi of the contract, to encode calls and decode results and state",
+		)
+	}
+	runLocalContractCmd.PersistentFlags().StringVar(
+		&runLocalActor,
+		"actor",
+		"",
+		"address calling the contract (empty address if unset)",
+	)
+	runLocalContractCmd.PersistentFlags().Uint64Var(
+		&runLocalMaxFuel,
+		"max-fuel",
+		genesis.Default().ContractMaxFuel,
+		"max fuel of the call",
+	)
+	runLocalContractCmd.PersistentFlags().Int64Var(
+		&runLocalMaxMemory,
+		"max-memory",
+		actions.ContractMaxMemory,
+		"max memory of the call in bytes",
+	)
+	runLocalContractCmd.PersistentFlags().DurationVar(
+		&runLocalMaxTime,
+		"max-time",
+		10*time.Second,
+		"max time of the call",
+	)
+	contractCmd.AddCommand(
+		infoContractCmd,
+		stateContractCmd,
+		bytecodeContractCmd,
+		diffContractCmd,
+		runLocalContractCmd,
 	)
 
 	// spam
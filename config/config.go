@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/hypersdk/trace"
 	"github.com/ava-labs/hypersdk/vm"
 
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
 	"github.com/containerman17/avalanche-polyglot-subnet/consts"
 	"github.com/containerman17/avalanche-polyglot-subnet/version"
 )
@@ -27,6 +28,7 @@ const (
 	defaultContinuousProfilerFrequency = 1 * time.Minute
 	defaultContinuousProfilerMaxFiles  = 10
 	defaultStoreTransactions           = true
+	defaultStoreContractEvents         = true
 )
 
 type Config struct {
@@ -62,6 +64,10 @@ type Config struct {
 	// State Sync
 	StateSyncServerDelay time.Duration `json:"stateSyncServerDelay"` // for testing
 
+	// Contracts
+	ContractMaxTime     time.Duration `json:"contractMaxTime"` // node-local, not part of consensus
+	StoreContractEvents bool          `json:"storeContractEvents"`
+
 	loaded               bool
 	nodeID               ids.NodeID
 	parsedExemptSponsors []codec.Address
@@ -102,6 +108,8 @@ func (c *Config) setDefault() {
 	c.StreamingBacklogSize = c.Config.GetStreamingBacklogSize()
 	c.VerifyAuth = c.Config.GetVerifyAuth()
 	c.StoreTransactions = defaultStoreTransactions
+	c.ContractMaxTime = actions.DefaultContractMaxTime
+	c.StoreContractEvents = defaultStoreContractEvents
 }
 
 func (c *Config) GetLogLevel() logging.Level                { return c.LogLevel }
@@ -138,6 +146,8 @@ func (c *Config) GetContinuousProfilerConfig() *profiler.Config {
 		MaxNumFiles: defaultContinuousProfilerMaxFiles,
 	}
 }
-func (c *Config) GetVerifyAuth() bool        { return c.VerifyAuth }
-func (c *Config) GetStoreTransactions() bool { return c.StoreTransactions }
-func (c *Config) Loaded() bool               { return c.loaded }
+func (c *Config) GetVerifyAuth() bool               { return c.VerifyAuth }
+func (c *Config) GetStoreTransactions() bool        { return c.StoreTransactions }
+func (c *Config) GetContractMaxTime() time.Duration { return c.ContractMaxTime }
+func (c *Config) GetStoreContractEvents() bool      { return c.StoreContractEvents }
+func (c *Config) Loaded() bool                      { return c.loaded }
@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
 )
 
 const (
@@ -35,4 +36,5 @@ func init() {
 var (
 	ActionRegistry *codec.TypeParser[chain.Action, *warp.Message, bool]
 	AuthRegistry   *codec.TypeParser[chain.Auth, *warp.Message, bool]
+	EngineRegistry *execution.Registry
 )
@@ -5,8 +5,13 @@ package consts
 
 const (
 	// Action TypeIDs
-	TransferID       uint8 = 0
-	CreateContractID uint8 = 1
+	TransferID               uint8 = 0
+	CreateContractID         uint8 = 1
+	CallContractID           uint8 = 2
+	UpgradeContractID        uint8 = 3
+	UploadCodeID             uint8 = 4
+	CreateContractFromCodeID uint8 = 5
+	DeleteContractID         uint8 = 6
 
 	// Auth TypeIDs
 	ED25519ID       uint8 = 0
@@ -0,0 +1,75 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/hypersdk/builder"
+	"github.com/ava-labs/hypersdk/chain"
+	"go.uber.org/zap"
+
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+)
+
+var _ builder.Builder = (*screeningBuilder)(nil)
+
+// maxScreenTime is the longest the mempool is locked for a screening, the
+// calls not reached are screened the next time
+const maxScreenTime = time.Second
+
+// screeningBuilder drops the contract calls of the mempool this node can't
+// execute within its wall-clock limit before it builds, see
+// [actions.ScreenCallContract]. The calls in blocks have no wall-clock limit,
+// so a slow call must never reach the builder.
+type screeningBuilder struct {
+	builder.Builder
+
+	c *Controller
+}
+
+// Queue is invoked when transactions are added to the mempool and after
+// every block.
+func (b *screeningBuilder) Queue(ctx context.Context) {
+	b.screen(ctx)
+	b.Builder.Queue(ctx)
+}
+
+func (b *screeningBuilder) Force(ctx context.Context) error {
+	b.screen(ctx)
+	return b.Builder.Force(ctx)
+}
+
+// screen dry runs the calls of the mempool not screened yet on top of the
+// preferred block.
+func (b *screeningBuilder) screen(ctx context.Context) {
+	blk, err := b.c.inner.PreferredBlock(ctx)
+	if err != nil {
+		b.c.inner.Logger().Warn("unable to load preferred block", zap.Error(err))
+		return
+	}
+	view, err := blk.View(ctx, false)
+	if err != nil {
+		b.c.inner.Logger().Debug("unable to load preferred state", zap.Error(err))
+		return
+	}
+	now := time.Now().UnixMilli()
+	rules := b.c.Rules(now).(actions.ContractRules)
+	err = b.c.inner.Mempool().Top(
+		ctx,
+		maxScreenTime,
+		func(ctx context.Context, tx *chain.Transaction) (bool, bool, error) {
+			call, ok := tx.Action.(*actions.CallContract)
+			if !ok || actions.ScreenCallContract(ctx, rules, view, now, tx.Auth.Actor(), call) {
+				return true, true, nil
+			}
+			b.c.inner.Logger().Warn("dropping contract call exceeding the wall-clock limit", zap.Stringer("txID", tx.ID()))
+			return true, false, nil
+		},
+	)
+	if err != nil {
+		b.c.inner.Logger().Warn("unable to screen mempool", zap.Error(err))
+	}
+}
@@ -10,12 +10,14 @@ import (
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/gossiper"
 	hrpc "github.com/ava-labs/hypersdk/rpc"
 	hstorage "github.com/ava-labs/hypersdk/storage"
+	"github.com/ava-labs/hypersdk/utils"
 	"github.com/ava-labs/hypersdk/vm"
 	"go.uber.org/zap"
 
@@ -86,6 +88,7 @@ func (c *Controller) Initialize(
 	}
 	c.snowCtx.Log.SetLevel(c.config.GetLogLevel())
 	snowCtx.Log.Info("initialized config", zap.Bool("loaded", c.config.Loaded()), zap.Any("contents", c.config))
+	actions.SetContractMaxTime(c.config.GetContractMaxTime())
 
 	c.genesis, err = genesis.New(genesisBytes, upgradeBytes)
 	if err != nil {
@@ -134,6 +137,7 @@ func (c *Controller) Initialize(
 			return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 		}
 	}
+	build = &screeningBuilder{Builder: build, c: c}
 	return c.config, c.genesis, build, gossip, blockDB, stateDB, apis, consts.ActionRegistry, consts.AuthRegistry, auth.Engines(), nil
 }
 
@@ -168,15 +172,47 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 			}
 		}
 		if result.Success {
-			switch tx.Action.(type) { //nolint:gocritic
+			switch action := tx.Action.(type) {
 			case *actions.Transfer:
 				c.metrics.transfer.Inc()
+			case *actions.CallContract:
+				c.metrics.callContract.Inc()
+				if c.config.GetStoreContractEvents() {
+					if err := c.storeContractEvents(ctx, batch, blk.Hght, uint32(i), tx.ID(), result.Output); err != nil {
+						return err
+					}
+				}
+			case *actions.UploadCode:
+				if err := storage.StoreCodeHash(ctx, batch, utils.ToID(action.Bytecode), action.EngineID); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	return batch.Write()
 }
 
+func (*Controller) storeContractEvents(
+	ctx context.Context,
+	batch database.KeyValueWriter,
+	height uint64,
+	txIndex uint32,
+	txID ids.ID,
+	output []byte,
+) error {
+	callOutput, err := actions.UnmarshalCallContractOutput(output)
+	if err != nil {
+		return err
+	}
+	for i, event := range callOutput.Events {
+		err := storage.StoreContractEvent(ctx, batch, event.Contract, height, txIndex, uint32(i), txID, event.Topic, event.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (*Controller) Rejected(context.Context, *chain.StatelessBlock) error {
 	return nil
 }
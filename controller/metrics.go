@@ -11,7 +11,8 @@ import (
 )
 
 type metrics struct {
-	transfer prometheus.Counter
+	transfer     prometheus.Counter
+	callContract prometheus.Counter
 }
 
 func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
@@ -21,11 +22,17 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "transfer",
 			Help:      "number of transfer actions",
 		}),
+		callContract: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "call_contract",
+			Help:      "number of call contract actions",
+		}),
 	}
 	r := prometheus.NewRegistry()
 	errs := wrappers.Errs{}
 	errs.Add(
 		r.Register(m.transfer),
+		r.Register(m.callContract),
 
 		gatherer.Register(consts.Name, r),
 	)
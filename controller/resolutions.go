@@ -5,12 +5,18 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	"github.com/containerman17/avalanche-polyglot-subnet/auth"
 	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
 	"github.com/containerman17/avalanche-polyglot-subnet/storage"
 )
@@ -54,3 +60,113 @@ func (c *Controller) GetContractStateFromState(
 ) ([]byte, error) {
 	return storage.GetContractStateFromState(ctx, c.inner.ReadState, acct)
 }
+
+func (c *Controller) GetContractStorageFromState(
+	ctx context.Context,
+	acct codec.Address,
+	key []byte,
+) ([]byte, error) {
+	return storage.GetContractStorageFromState(ctx, c.inner.ReadState, acct, key)
+}
+
+func (c *Controller) GetContractMetadataFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (*storage.ContractMetadata, error) {
+	return storage.GetContractMetadataFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetContractDepositFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (uint64, error) {
+	return storage.GetContractDepositFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetContractEvents(
+	ctx context.Context,
+	acct codec.Address,
+	topic []byte,
+	fromHeight uint64,
+	toHeight uint64,
+	cursor *storage.ContractEventCursor,
+	limit int,
+) ([]*storage.ContractEvent, *storage.ContractEventCursor, error) {
+	return storage.GetContractEvents(ctx, c.metaDB, acct, topic, fromHeight, toHeight, cursor, limit)
+}
+
+func (c *Controller) GetCodeHashes(
+	ctx context.Context,
+	start ids.ID,
+	limit int,
+) ([]*storage.UploadedCode, error) {
+	return storage.GetCodeHashes(ctx, c.metaDB, start, limit)
+}
+
+func (c *Controller) SimulateCallContract(
+	ctx context.Context,
+	actor codec.Address,
+	addr codec.Address,
+	payload []byte,
+) (*actions.CallContractOutput, []byte, uint64, error) {
+	// there is no transaction, so the clock is the node time and the random
+	// numbers are seeded from an empty tx ID
+	now := time.Now().UnixMilli()
+	rules := c.genesis.Rules(now, c.inner.NetworkID(), c.inner.ChainID())
+	return actions.SimulateCallContract(ctx, rules, storage.ReadState(c.inner.ReadState).Immutable(), now, actor, addr, payload, 0)
+}
+
+// EstimateAction returns the units a transaction of [action] signed with
+// ED25519 by [actor] may consume, the fee at the current unit prices and the
+// fuel consumed by a dry run of contract calls. The units of a call are those
+// of a call sent with that fuel as its MaxFuel. Like [chain.Transaction.MaxUnits],
+// every declared key is charged for all its chunks.
+func (c *Controller) EstimateAction(
+	ctx context.Context,
+	actor codec.Address,
+	action chain.Action,
+) (fees.Dimensions, uint64, uint64, error) {
+	now := time.Now().UnixMilli()
+	rules := c.genesis.Rules(now, c.inner.NetworkID(), c.inner.ChainID())
+
+	var fuelConsumed uint64
+	actionUnits := action.MaxComputeUnits(rules)
+	if call, ok := action.(*actions.CallContract); ok {
+		var err error
+		fuelConsumed, err = actions.DryRunCallContract(ctx, rules, storage.ReadState(c.inner.ReadState).Immutable(), now, actor, call)
+		if err != nil {
+			return fees.Dimensions{}, 0, 0, err
+		}
+		actionUnits = actions.CallContractComputeUnits + actions.FuelComputeUnits(rules, max(fuelConsumed, 1))
+	}
+
+	var units fees.Dimensions
+	// base, no warp message, action and auth
+	bandwidth := chain.BaseSize + codec.BytesLen(nil) + hconsts.ByteLen + action.Size() + hconsts.ByteLen + auth.ED25519Size
+	units[fees.Bandwidth] = uint64(bandwidth)
+	units[fees.Compute] = rules.GetBaseComputeUnits() + auth.ED25519ComputeUnits + actionUnits
+
+	stateKeys := action.StateKeys(actor, ids.Empty)
+	for key, permissions := range c.StateManager().SponsorStateKeys(actor) {
+		stateKeys.Add(key, permissions)
+	}
+	for key := range stateKeys {
+		chunks, ok := keys.MaxChunks([]byte(key))
+		if !ok {
+			return fees.Dimensions{}, 0, 0, chain.ErrInvalidKeyValue
+		}
+		units[fees.StorageRead] += rules.GetStorageKeyReadUnits() + uint64(chunks)*rules.GetStorageValueReadUnits()
+		units[fees.StorageAllocate] += rules.GetStorageKeyAllocateUnits() + uint64(chunks)*rules.GetStorageValueAllocateUnits()
+		units[fees.StorageWrite] += rules.GetStorageKeyWriteUnits() + uint64(chunks)*rules.GetStorageValueWriteUnits()
+	}
+
+	unitPrices, err := c.inner.UnitPrices(ctx)
+	if err != nil {
+		return fees.Dimensions{}, 0, 0, err
+	}
+	fee, err := fees.MulSum(unitPrices, units)
+	if err != nil {
+		return fees.Dimensions{}, 0, 0, err
+	}
+	return units, fee, fuelConsumed, nil
+}
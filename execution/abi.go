@@ -0,0 +1,340 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+// HostModule is the import module contracts link against to call the host.
+// Its functions never change, new ones are only added.
+//
+//	input_size() -> i32
+//	input_read(ptr: i32)
+//	actor_size() -> i32
+//	actor_read(ptr: i32)
+//	set_result(ptr: i32, len: i32)
+//	storage_read(key_ptr: i32, key_len: i32, value_ptr: i32, value_cap: i32) -> i32
+//	storage_write(key_ptr: i32, key_len: i32, value_ptr: i32, value_len: i32)
+//	abort(msg_ptr: i32, msg_len: i32)
+//	value() -> i64
+//	balance() -> i64
+//	transfer(to_ptr: i32, amount: i64)
+//	call(to_ptr: i32, input_ptr: i32, input_len: i32, value: i64, max_fuel: i64) -> i32
+//	call_result_size() -> i32
+//	call_result_read(ptr: i32)
+//	emit(topic_ptr: i32, topic_len: i32, data_ptr: i32, data_len: i32)
+//	self_destruct(beneficiary_ptr: i32)
+//
+// storage_read returns -1 if the key is not set, the length of the value
+// otherwise. At most value_cap bytes are copied, so a value larger than the
+// buffer can be read again with a bigger one.
+//
+// Amounts and fuel are unsigned 64-bit integers passed as i64. Addresses are
+// codec.AddressLen bytes long. Each transfer consumes [TransferFuel] and each
+// call [CallFuel] plus the fuel consumed by the callee.
+//
+// emit records an event, it consumes [EventFuel] plus [EventByteFuel] per
+// byte. The topic is 1 to [MaxEventTopicLen] bytes long.
+//
+// self_destruct deletes the contract when the call succeeds: its bytecode,
+// state, metadata and declared storage keys are removed and its balance is
+// sent to the beneficiary, which must be a declared recipient. Immutable
+// contracts can't destruct. It consumes [SelfDestructFuel].
+//
+// call returns 0 if the callee succeeded and 1 if it failed, its changes are
+// then discarded. The result of the last call, or its error message, is read
+// with call_result_*.
+const HostModule = "polyglot_v1"
+
+var errOutOfBounds = errors.New("memory access out of bounds")
+
+// DefineHostModule registers the functions of [HostModule] backed by [host].
+// A failing host function traps the call.
+func DefineHostModule(linker *wasmtime.Linker, host *Host) error {
+	funcs := map[string]interface{}{
+		"input_size": func() int32 {
+			return int32(len(host.Input()))
+		},
+		"input_read": func(caller *wasmtime.Caller, ptr int32) *wasmtime.Trap {
+			return writeMemory(caller, ptr, host.Input())
+		},
+		"actor_size": func() int32 {
+			return int32(len(host.Actor()))
+		},
+		"actor_read": func(caller *wasmtime.Caller, ptr int32) *wasmtime.Trap {
+			return writeMemory(caller, ptr, host.Actor())
+		},
+		"set_result": func(caller *wasmtime.Caller, ptr, length int32) *wasmtime.Trap {
+			result, ok := MemorySlice(caller, ptr, length)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			return toTrap(host.SetResult(result))
+		},
+		"storage_read": func(caller *wasmtime.Caller, keyPtr, keyLen, valuePtr, valueCap int32) (int32, *wasmtime.Trap) {
+			key, ok := MemorySlice(caller, keyPtr, keyLen)
+			if !ok {
+				return 0, toTrap(errOutOfBounds)
+			}
+			value, found, err := host.StorageRead(key)
+			if err != nil {
+				return 0, toTrap(err)
+			}
+			if !found {
+				return -1, nil
+			}
+			buf, ok := MemorySlice(caller, valuePtr, valueCap)
+			if !ok {
+				return 0, toTrap(errOutOfBounds)
+			}
+			copy(buf, value)
+			return int32(len(value)), nil
+		},
+		"storage_write": func(caller *wasmtime.Caller, keyPtr, keyLen, valuePtr, valueLen int32) *wasmtime.Trap {
+			key, ok := MemorySlice(caller, keyPtr, keyLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			value, ok := MemorySlice(caller, valuePtr, valueLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			return toTrap(host.StorageWrite(key, value))
+		},
+		"abort": func(caller *wasmtime.Caller, ptr, length int32) *wasmtime.Trap {
+			message, _ := MemorySlice(caller, ptr, length)
+			return toTrap(host.Abort(message))
+		},
+		"value": func() int64 {
+			return int64(host.Value())
+		},
+		"balance": func() (int64, *wasmtime.Trap) {
+			balance, err := host.Balance()
+			return int64(balance), toTrap(err)
+		},
+		"transfer": func(caller *wasmtime.Caller, toPtr int32, amount int64) *wasmtime.Trap {
+			to, ok := MemorySlice(caller, toPtr, codec.AddressLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			return toTrap(host.Transfer(codec.Address(to), uint64(amount)))
+		},
+		"call": func(caller *wasmtime.Caller, toPtr, inputPtr, inputLen int32, value, maxFuel int64) (int32, *wasmtime.Trap) {
+			to, ok := MemorySlice(caller, toPtr, codec.AddressLen)
+			if !ok {
+				return 0, toTrap(errOutOfBounds)
+			}
+			input, ok := MemorySlice(caller, inputPtr, inputLen)
+			if !ok {
+				return 0, toTrap(errOutOfBounds)
+			}
+			success, err := host.Call(codec.Address(to), bytes.Clone(input), uint64(value), uint64(maxFuel))
+			if err != nil {
+				return 0, toTrap(err)
+			}
+			if !success {
+				return 1, nil
+			}
+			return 0, nil
+		},
+		"call_result_size": func() int32 {
+			return int32(len(host.CallResult()))
+		},
+		"call_result_read": func(caller *wasmtime.Caller, ptr int32) *wasmtime.Trap {
+			return writeMemory(caller, ptr, host.CallResult())
+		},
+		"emit": func(caller *wasmtime.Caller, topicPtr, topicLen, dataPtr, dataLen int32) *wasmtime.Trap {
+			topic, ok := MemorySlice(caller, topicPtr, topicLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			data, ok := MemorySlice(caller, dataPtr, dataLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			return toTrap(host.Emit(topic, data))
+		},
+		"self_destruct": func(caller *wasmtime.Caller, beneficiaryPtr int32) *wasmtime.Trap {
+			beneficiary, ok := MemorySlice(caller, beneficiaryPtr, codec.AddressLen)
+			if !ok {
+				return toTrap(errOutOfBounds)
+			}
+			return toTrap(host.SelfDestruct(codec.Address(beneficiary)))
+		},
+	}
+	for name, fn := range funcs {
+		if err := linker.FuncWrap(HostModule, name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMemory(caller *wasmtime.Caller, ptr int32, data []byte) *wasmtime.Trap {
+	buf, ok := MemorySlice(caller, ptr, int32(len(data)))
+	if !ok {
+		return toTrap(errOutOfBounds)
+	}
+	copy(buf, data)
+	return nil
+}
+
+func toTrap(err error) *wasmtime.Trap {
+	if err == nil {
+		return nil
+	}
+	return wasmtime.NewTrap(err.Error())
+}
+
+// HostCallPrefix starts a request to [HostModule] from contracts that cannot
+// import functions, like the JS ones, which only have the standard streams.
+// A single write to stderr starting with the prefix is a request and the
+// response to the last request is read back from stdin.
+//
+// A request is the prefix, the call ID and its arguments:
+//
+//	HostCallInput
+//	HostCallActor
+//	HostCallSetResult    result
+//	HostCallStorageRead  key
+//	HostCallStorageWrite key length (u32 little endian), key, value
+//	HostCallAbort        message
+//	HostCallValue
+//	HostCallBalance
+//	HostCallTransfer     address, amount (u64 little endian)
+//	HostCallCall         address, value (u64 little endian), max fuel (u64 little endian), input
+//	HostCallEmit         topic length (u32 little endian), topic, data
+//	HostCallSelfDestruct beneficiary address
+//
+// A response is a status followed by the returned value, if any. Amounts are
+// returned as u64 little endian. A call responds with [HostCallOK] or
+// [HostCallFailed] followed by the result of the callee.
+var HostCallPrefix = []byte{0x0, 'p', 'v', '1'}
+
+const (
+	HostCallInput byte = iota + 1
+	HostCallActor
+	HostCallSetResult
+	HostCallStorageRead
+	HostCallStorageWrite
+	HostCallAbort
+	HostCallValue
+	HostCallBalance
+	HostCallTransfer
+	HostCallCall
+	HostCallEmit
+	HostCallSelfDestruct
+)
+
+const (
+	HostCallOK byte = iota
+	HostCallNotFound
+	HostCallFailed
+)
+
+var (
+	ErrInvalidHostCall = errors.New("invalid host call")
+	errEmptyHostCall   = errors.New("empty host call")
+)
+
+// IsHostCall reports whether [data] written to stderr is a host call request.
+func IsHostCall(data []byte) bool {
+	return bytes.HasPrefix(data, HostCallPrefix)
+}
+
+// HandleHostCall executes a request, see [HostCallPrefix], and returns the
+// response.
+func HandleHostCall(host *Host, request []byte) ([]byte, error) {
+	request = bytes.TrimPrefix(request, HostCallPrefix)
+	if len(request) == 0 {
+		return nil, errEmptyHostCall
+	}
+	args := request[1:]
+	switch request[0] {
+	case HostCallInput:
+		return append([]byte{HostCallOK}, host.Input()...), nil
+	case HostCallActor:
+		return append([]byte{HostCallOK}, host.Actor()...), nil
+	case HostCallSetResult:
+		return []byte{HostCallOK}, host.SetResult(args)
+	case HostCallStorageRead:
+		value, found, err := host.StorageRead(args)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return []byte{HostCallNotFound}, nil
+		}
+		return append([]byte{HostCallOK}, value...), nil
+	case HostCallStorageWrite:
+		key, value, ok := splitPrefixed(args)
+		if !ok {
+			return nil, ErrInvalidHostCall
+		}
+		return []byte{HostCallOK}, host.StorageWrite(key, value)
+	case HostCallAbort:
+		return nil, host.Abort(args)
+	case HostCallValue:
+		return binary.LittleEndian.AppendUint64([]byte{HostCallOK}, host.Value()), nil
+	case HostCallBalance:
+		balance, err := host.Balance()
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64([]byte{HostCallOK}, balance), nil
+	case HostCallTransfer:
+		if len(args) != codec.AddressLen+8 {
+			return nil, ErrInvalidHostCall
+		}
+		to := codec.Address(args[:codec.AddressLen])
+		amount := binary.LittleEndian.Uint64(args[codec.AddressLen:])
+		return []byte{HostCallOK}, host.Transfer(to, amount)
+	case HostCallCall:
+		if len(args) < codec.AddressLen+16 {
+			return nil, ErrInvalidHostCall
+		}
+		to := codec.Address(args[:codec.AddressLen])
+		value := binary.LittleEndian.Uint64(args[codec.AddressLen:])
+		maxFuel := binary.LittleEndian.Uint64(args[codec.AddressLen+8:])
+		input := bytes.Clone(args[codec.AddressLen+16:])
+		success, err := host.Call(to, input, value, maxFuel)
+		if err != nil {
+			return nil, err
+		}
+		status := HostCallOK
+		if !success {
+			status = HostCallFailed
+		}
+		return append([]byte{status}, host.CallResult()...), nil
+	case HostCallEmit:
+		topic, data, ok := splitPrefixed(args)
+		if !ok {
+			return nil, ErrInvalidHostCall
+		}
+		return []byte{HostCallOK}, host.Emit(topic, data)
+	case HostCallSelfDestruct:
+		if len(args) != codec.AddressLen {
+			return nil, ErrInvalidHostCall
+		}
+		return []byte{HostCallOK}, host.SelfDestruct(codec.Address(args))
+	default:
+		return nil, ErrInvalidHostCall
+	}
+}
+
+// splitPrefixed splits [args] made of the length of the first part (u32
+// little endian), the first part and the second one.
+func splitPrefixed(args []byte) ([]byte, []byte, bool) {
+	if len(args) < 4 {
+		return nil, nil, false
+	}
+	firstLen := binary.LittleEndian.Uint32(args)
+	if uint64(firstLen) > uint64(len(args)-4) {
+		return nil, nil, false
+	}
+	return args[4 : 4+firstLen], args[4+firstLen:], true
+}
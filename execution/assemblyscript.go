@@ -0,0 +1,46 @@
+package execution
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+// AssemblyScriptModule provides the abort function AssemblyScript modules
+// import for failed assertions and uncaught errors:
+//
+//	abort(msg_ptr: i32, file_ptr: i32, line: i32, column: i32)
+//
+// It aborts the call like the abort of [HostModule], the message includes the
+// location of the error.
+const AssemblyScriptModule = "env"
+
+func defineAssemblyScript(linker *wasmtime.Linker, host *Host) error {
+	return linker.FuncWrap(AssemblyScriptModule, "abort", func(caller *wasmtime.Caller, msgPtr, filePtr, line, column int32) *wasmtime.Trap {
+		message := fmt.Sprintf("%s at %s:%d:%d", assemblyScriptString(caller, msgPtr), assemblyScriptString(caller, filePtr), line, column)
+		return toTrap(host.Abort([]byte(message)))
+	})
+}
+
+// assemblyScriptString decodes the UTF-16 string at [ptr], its length in bytes
+// is stored in the 4 bytes before it. Invalid strings are empty.
+func assemblyScriptString(caller *wasmtime.Caller, ptr int32) string {
+	if ptr < 4 {
+		return ""
+	}
+	header, ok := MemorySlice(caller, ptr-4, 4)
+	if !ok {
+		return ""
+	}
+	data, ok := MemorySlice(caller, ptr, int32(binary.LittleEndian.Uint32(header)))
+	if !ok {
+		return ""
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var (
+	// ErrTimeout is returned when a call runs longer than
+	// [ExecParams.MaxTime]. Unlike running out of fuel or memory, it depends
+	// on the machine executing the call, so it must not decide the outcome of
+	// a transaction.
+	ErrTimeout = errors.New("execution timed out")
+
+	// ErrOutOfFuel is returned when a metered host function needs more fuel
+	// than the call has left.
+	ErrOutOfFuel = errors.New("all fuel consumed")
+
+	ErrUnknownEngine   = errors.New("unknown engine")
+	ErrDuplicateEngine = errors.New("engine already registered")
+)
+
+// Engine runs the contracts compiled for one runtime. Engines must be safe for
+// concurrent use and behave the same on every validator.
+type Engine interface {
+	// Validate reports whether [bytecode] can be executed by the engine.
+	Validate(bytecode []byte) error
+	// Execute returns the result of a failed call along with the error once
+	// the contract ran, only its FuelConsumed, TimeTaken and StdErr are set.
+	Execute(params ExecParams) (*ExecResult, error)
+}
+
+// ExecParams describe a call. Contracts using the stdio protocol receive
+// CurrentState, Payload and Actor as JSON on stdin, the others get them from
+// the host functions.
+type ExecParams struct {
+	MaxFuel      uint64        `json:"-"`
+	MaxTime      time.Duration `json:"-"` //wall-clock, node-local, 0 for none
+	MaxMemory    int64         `json:"-"`
+	MaxOutput    int           `json:"-"` //per stream, stdout and stderr, and for the result
+	Timestamp    int64         `json:"-"` //unix ms, returned by the contract clock
+	Seed         []byte        `json:"-"` //source of the contract random numbers
+	Bytecode     *[]byte       `json:"-"`
+	Storage      Storage       `json:"-"` //keys other than the state, optional
+	Value        uint64        `json:"-"` //attached to the call, part of the balance
+	Chain        Chain         `json:"-"` //balances and nested calls, optional
+	CurrentState []byte        `json:"currentState"`
+	Payload      []byte        `json:"payload"`
+	Actor        []byte        `json:"actor"`
+}
+
+type ExecResult struct {
+	FuelConsumed  uint64
+	TimeTaken     time.Duration
+	UpdatedState  *[]byte //nil if no update
+	StorageWrites map[string][]byte
+	StdErr        []byte
+	Result        []byte
+
+	// SelfDestruct is the beneficiary of the balance if the contract
+	// destructed itself, nil otherwise
+	SelfDestruct *codec.Address
+}
+
+// Registry holds the engines contracts can be deployed for, keyed by the
+// engine ID recorded with every contract. An ID must never be reassigned.
+type Registry struct {
+	engines map[uint8]Engine
+}
+
+func NewRegistry() *Registry {
+	return &Registry{engines: map[uint8]Engine{}}
+}
+
+// Register adds [engine] under [id]. It must be done before the chain
+// processes any block.
+func (r *Registry) Register(id uint8, engine Engine) error {
+	if _, ok := r.engines[id]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateEngine, id)
+	}
+	r.engines[id] = engine
+	return nil
+}
+
+// Get returns [ErrUnknownEngine] if no engine is registered under [id].
+func (r *Registry) Get(id uint8) (Engine, error) {
+	engine, ok := r.engines[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownEngine, id)
+	}
+	return engine, nil
+}
@@ -0,0 +1,331 @@
+package execution
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var (
+	ErrAborted            = errors.New("contract aborted")
+	ErrStorageUnavailable = errors.New("contract storage unavailable")
+	ErrResultTooLarge     = errors.New("result size limit exceeded")
+	ErrInsufficientFunds  = errors.New("insufficient contract balance")
+	ErrTransferZero       = errors.New("transfer amount is zero")
+	ErrChainUnavailable   = errors.New("chain unavailable")
+	ErrInvalidEventTopic  = errors.New("invalid event topic")
+	ErrEventTooLarge      = errors.New("event size limit exceeded")
+)
+
+// Fuel consumed by the metered host functions, on top of the fuel of the
+// executed instructions
+const (
+	TransferFuel = 10_000
+	CallFuel     = 10_000 // plus the fuel consumed by the callee
+	EventFuel    = 10_000 // plus EventByteFuel per byte of topic and data
+
+	SelfDestructFuel = 10_000
+
+	EventByteFuel = 100
+)
+
+// MaxEventTopicLen bounds the topic of an event, which can't be empty.
+const MaxEventTopicLen = 32
+
+// StateKey is the storage key of the contract state blob, the only state
+// contracts using the stdio protocol have.
+var StateKey = []byte{}
+
+// Storage gives a call read access to the keys of the executed contract.
+// Writes are buffered by [Host] and returned to the caller.
+type Storage interface {
+	// Get returns false if [key] is not set
+	Get(key []byte) ([]byte, bool, error)
+}
+
+// Chain gives a call access to the state outside of the contract storage.
+// Changes are applied as they are made and discarded with the call if it fails.
+type Chain interface {
+	// Balance returns the balance of the executed contract.
+	Balance() (uint64, error)
+
+	// Transfer moves [amount] from the executed contract to [to].
+	Transfer(to codec.Address, amount uint64) error
+
+	// Call executes the contract at [to] with [input] and [value] taken from
+	// the executed contract. An error means the call could not be made and
+	// must stop the caller, a failing callee is reported in the result.
+	Call(to codec.Address, input []byte, value uint64, maxFuel uint64) (*CallResult, error)
+
+	// Emit records an event of the executed contract.
+	Emit(topic []byte, data []byte) error
+}
+
+// CallResult is the outcome of [Chain.Call]. The result of a failed call is
+// its error message.
+type CallResult struct {
+	Success      bool
+	Result       []byte
+	FuelConsumed uint64
+}
+
+// FuelMeter gives the metered host functions access to the fuel of the call.
+type FuelMeter interface {
+	Remaining() (uint64, error)
+	Consume(fuel uint64) error
+}
+
+// HostParams describe the call served by a [Host].
+type HostParams struct {
+	Input     []byte
+	Actor     []byte
+	MaxResult int
+
+	// State is the value of [StateKey] and Storage serves every other key, it
+	// may be nil.
+	State   []byte
+	Storage Storage
+
+	// Value is the amount attached to the call, already part of the contract
+	// balance. Chain may be nil.
+	Value uint64
+	Chain Chain
+}
+
+// Host implements the host functions available to a single call, see
+// [HostModule]. It is not safe for concurrent use.
+type Host struct {
+	input     []byte
+	actor     []byte
+	maxResult int
+
+	state   []byte
+	storage Storage
+	writes  map[string][]byte
+
+	value      uint64
+	chain      Chain
+	fuel       FuelMeter
+	callResult []byte
+	chainErr   error
+	// beneficiary is set once the contract destructed itself
+	beneficiary *codec.Address
+
+	used         bool
+	result       []byte
+	aborted      bool
+	abortMessage string
+}
+
+func NewHost(params HostParams) *Host {
+	return &Host{
+		input:     params.Input,
+		actor:     params.Actor,
+		maxResult: params.MaxResult,
+		state:     params.State,
+		storage:   params.Storage,
+		writes:    map[string][]byte{},
+		value:     params.Value,
+		chain:     params.Chain,
+	}
+}
+
+// SetFuelMeter sets the fuel of the call. Without it the metered host
+// functions are free and nested calls get the fuel they ask for.
+func (h *Host) SetFuelMeter(fuel FuelMeter) {
+	h.fuel = fuel
+}
+
+// Used reports whether the contract called any host function. Contracts that
+// did not are using the legacy stdio protocol.
+func (h *Host) Used() bool {
+	return h.used
+}
+
+func (h *Host) Input() []byte {
+	h.used = true
+	return h.input
+}
+
+func (h *Host) Actor() []byte {
+	h.used = true
+	return h.actor
+}
+
+func (h *Host) SetResult(result []byte) error {
+	h.used = true
+	if len(result) > h.maxResult {
+		return ErrResultTooLarge
+	}
+	h.result = bytes.Clone(result)
+	return nil
+}
+
+func (h *Host) StorageRead(key []byte) ([]byte, bool, error) {
+	h.used = true
+	if len(key) == 0 {
+		return h.state, len(h.state) > 0, nil
+	}
+	if value, ok := h.writes[string(key)]; ok {
+		// an empty value removes the key
+		return value, len(value) > 0, nil
+	}
+	if h.storage == nil {
+		return nil, false, ErrStorageUnavailable
+	}
+	return h.storage.Get(key)
+}
+
+func (h *Host) StorageWrite(key []byte, value []byte) error {
+	h.used = true
+	if len(key) == 0 {
+		h.state = bytes.Clone(value)
+		return nil
+	}
+	if h.storage == nil {
+		return ErrStorageUnavailable
+	}
+	h.writes[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+func (h *Host) Value() uint64 {
+	h.used = true
+	return h.value
+}
+
+func (h *Host) Balance() (uint64, error) {
+	h.used = true
+	if h.chain == nil {
+		return 0, ErrChainUnavailable
+	}
+	return h.chain.Balance()
+}
+
+func (h *Host) Transfer(to codec.Address, amount uint64) error {
+	h.used = true
+	if err := h.consumeFuel(TransferFuel); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return ErrTransferZero
+	}
+	if h.chain == nil {
+		return ErrChainUnavailable
+	}
+	return h.chain.Transfer(to, amount)
+}
+
+// Call executes another contract with at most [maxFuel], bounded by the fuel
+// left, and reports whether it succeeded. Its result is available through
+// [Host.CallResult] until the next call.
+func (h *Host) Call(to codec.Address, input []byte, value uint64, maxFuel uint64) (bool, error) {
+	h.used = true
+	if err := h.consumeFuel(CallFuel); err != nil {
+		return false, err
+	}
+	if h.chain == nil {
+		return false, ErrChainUnavailable
+	}
+	if h.fuel != nil {
+		remaining, err := h.fuel.Remaining()
+		if err != nil {
+			return false, err
+		}
+		maxFuel = min(maxFuel, remaining)
+	}
+	res, err := h.chain.Call(to, input, value, maxFuel)
+	if err != nil {
+		h.chainErr = err
+		return false, err
+	}
+	h.callResult = res.Result
+	return res.Success, h.consumeFuel(res.FuelConsumed)
+}
+
+// Emit records an event of [topic]. Its data is bounded like the result.
+func (h *Host) Emit(topic []byte, data []byte) error {
+	h.used = true
+	if err := h.consumeFuel(EventFuel + uint64(len(topic)+len(data))*EventByteFuel); err != nil {
+		return err
+	}
+	if len(topic) == 0 || len(topic) > MaxEventTopicLen {
+		return ErrInvalidEventTopic
+	}
+	if len(data) > h.maxResult {
+		return ErrEventTooLarge
+	}
+	if h.chain == nil {
+		return ErrChainUnavailable
+	}
+	return h.chain.Emit(bytes.Clone(topic), bytes.Clone(data))
+}
+
+// SelfDestruct deletes the contract once the call succeeds and sends its
+// balance to [beneficiary], see [ExecResult.SelfDestruct].
+func (h *Host) SelfDestruct(beneficiary codec.Address) error {
+	h.used = true
+	if err := h.consumeFuel(SelfDestructFuel); err != nil {
+		return err
+	}
+	if h.chain == nil {
+		return ErrChainUnavailable
+	}
+	h.beneficiary = &beneficiary
+	return nil
+}
+
+// Beneficiary returns nil unless the contract destructed itself.
+func (h *Host) Beneficiary() *codec.Address {
+	return h.beneficiary
+}
+
+func (h *Host) CallResult() []byte {
+	h.used = true
+	return h.callResult
+}
+
+// ChainError returns the error that prevented a nested call, if any. The trap
+// it caused only keeps the message.
+func (h *Host) ChainError() error {
+	return h.chainErr
+}
+
+func (h *Host) consumeFuel(fuel uint64) error {
+	if h.fuel == nil {
+		return nil
+	}
+	return h.fuel.Consume(fuel)
+}
+
+// Abort records [message] and returns the error that must stop the call.
+func (h *Host) Abort(message []byte) error {
+	h.used = true
+	h.aborted = true
+	h.abortMessage = string(message)
+	return h.AbortError()
+}
+
+// AbortError returns nil unless the contract aborted.
+func (h *Host) AbortError() error {
+	if !h.aborted {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrAborted, h.abortMessage)
+}
+
+func (h *Host) Result() []byte {
+	return h.result
+}
+
+// State is the value of [StateKey] at the end of the call.
+func (h *Host) State() []byte {
+	return h.state
+}
+
+// Writes returns the values written to keys other than [StateKey].
+func (h *Host) Writes() map[string][]byte {
+	return h.writes
+}
@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"encoding/binary"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+// MemorySlice returns [length] bytes of the memory exported by the caller
+// starting at [ptr], or false if the range is out of bounds.
+func MemorySlice(caller *wasmtime.Caller, ptr, length int32) ([]byte, bool) {
+	export := caller.GetExport("memory")
+	if export == nil || export.Memory() == nil {
+		return nil, false
+	}
+	data := export.Memory().UnsafeData(caller)
+	start, end := uint64(uint32(ptr)), uint64(uint32(ptr))+uint64(uint32(length))
+	if end > uint64(len(data)) {
+		return nil, false
+	}
+	return data[start:end], true
+}
+
+func PutUint32(caller *wasmtime.Caller, ptr int32, value uint32) bool {
+	buf, ok := MemorySlice(caller, ptr, 4)
+	if ok {
+		binary.LittleEndian.PutUint32(buf, value)
+	}
+	return ok
+}
+
+func PutUint64(caller *wasmtime.Caller, ptr int32, value uint64) bool {
+	buf, ok := MemorySlice(caller, ptr, 8)
+	if ok {
+		binary.LittleEndian.PutUint64(buf, value)
+	}
+	return ok
+}
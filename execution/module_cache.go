@@ -0,0 +1,97 @@
+package execution
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+const DefaultModuleCacheSize = 256
+
+type ModuleCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+type cachedModule struct {
+	hash   [sha256.Size]byte
+	module *wasmtime.Module
+}
+
+// ModuleCache is a bounded LRU of compiled user modules keyed by the hash of
+// their bytecode.
+type ModuleCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is the most recently used
+	entries  map[[sha256.Size]byte]*list.Element
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func NewModuleCache(capacity int) *ModuleCache {
+	return &ModuleCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[[sha256.Size]byte]*list.Element{},
+	}
+}
+
+// GetOrCompile returns the cached module for [bytecode], compiling it with
+// [engine] on a miss.
+func (c *ModuleCache) GetOrCompile(engine *wasmtime.Engine, bytecode []byte) (*wasmtime.Module, error) {
+	hash := sha256.Sum256(bytecode)
+
+	c.mu.Lock()
+	if elem, ok := c.entries[hash]; ok {
+		c.hits++
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*cachedModule).module, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Compile outside of the lock, so misses on other contracts are not
+	// serialized behind this one.
+	module, err := wasmtime.NewModule(engine, bytecode) //Serialized wasm module does not add any performance benefits
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[hash]; ok {
+		// Compiled concurrently by another call
+		c.order.MoveToFront(elem)
+		return elem.Value.(*cachedModule).module, nil
+	}
+	c.entries[hash] = c.order.PushFront(&cachedModule{hash, module})
+	for c.order.Len() > c.capacity {
+		// Evicted modules are not closed as they may still be instantiated by
+		// an in-flight call, the finalizer releases them instead.
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedModule).hash)
+		c.evictions++
+	}
+	return module, nil
+}
+
+// Stats reports the usage of the cache.
+func (c *ModuleCache) Stats() ModuleCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ModuleCacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.order.Len(),
+	}
+}
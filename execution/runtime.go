@@ -0,0 +1,309 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+var (
+	ErrInvalidModule    = errors.New("invalid wasm module")
+	ErrImportNotAllowed = errors.New("import not allowed")
+	ErrImportMismatch   = errors.New("import type mismatch")
+	ErrStartNotExported = errors.New("_start is not exported")
+)
+
+// The wasmtime engine holds the compilation settings shared by every module,
+// so a single one is created per process and reused by all the engines.
+var (
+	sharedEngineOnce sync.Once
+	sharedEngine     *wasmtime.Engine
+)
+
+// EngineConfigVersion must be bumped whenever [newEngineConfig] changes, so
+// stale compiled artifacts are not loaded.
+const EngineConfigVersion = 3
+
+// epochTick is the resolution of the wall-clock limit of a call
+const epochTick = time.Millisecond
+
+// noEpochDeadline is the deadline of the calls without a wall-clock limit,
+// about 50 days of ticks
+const noEpochDeadline = math.MaxUint32
+
+// newEngineConfig only enables features that behave the same on every
+// validator.
+func newEngineConfig() *wasmtime.Config {
+	config := wasmtime.NewConfig()
+	config.SetConsumeFuel(true)
+	config.SetEpochInterruption(true)
+	config.SetWasmThreads(false)
+	config.EnableCraneliftFlag("enable_nan_canonicalization")
+	return config
+}
+
+// SharedEngine returns the wasmtime engine every module must be compiled
+// with.
+func SharedEngine() *wasmtime.Engine {
+	sharedEngineOnce.Do(func() {
+		engine := wasmtime.NewEngineWithConfig(newEngineConfig())
+		sharedEngine = engine
+
+		go func() {
+			for range time.Tick(epochTick) {
+				engine.IncrementEpoch()
+			}
+		}()
+	})
+	return sharedEngine
+}
+
+// ValidateModule checks that every import of [module] is defined, with the
+// same type, by the linker of a call using [link], and that [module] exports
+// a _start function.
+func ValidateModule(module *wasmtime.Module, link Linker) error {
+	host := NewHost(HostParams{})
+	store := wasmtime.NewStore(SharedEngine())
+	defer store.Close()
+	linker, err := newLinker(store, newWasiEnv(ExecParams{}, host), host, link)
+	if err != nil {
+		return err
+	}
+	for _, imp := range module.Imports() {
+		name := ""
+		if imp.Name() != nil {
+			name = *imp.Name()
+		}
+		extern := linker.Get(store, imp.Module(), name)
+		if extern == nil {
+			return fmt.Errorf("%w: %s.%s", ErrImportNotAllowed, imp.Module(), name)
+		}
+		if !importMatches(store, imp.Type(), extern) {
+			return fmt.Errorf("%w: %s.%s", ErrImportMismatch, imp.Module(), name)
+		}
+	}
+	for _, exp := range module.Exports() {
+		if exp.Name() == "_start" && exp.Type().FuncType() != nil {
+			return nil
+		}
+	}
+	return ErrStartNotExported
+}
+
+// importMatches reports whether [extern] can satisfy an import of type [ty].
+// Like at instantiation, an imported memory must be at least as large as the
+// import minimum and no larger than its maximum.
+func importMatches(store *wasmtime.Store, ty *wasmtime.ExternType, extern *wasmtime.Extern) bool {
+	switch {
+	case ty.FuncType() != nil:
+		fn := extern.Func()
+		if fn == nil {
+			return false
+		}
+		got := fn.Type(store)
+		return valTypesEqual(ty.FuncType().Params(), got.Params()) &&
+			valTypesEqual(ty.FuncType().Results(), got.Results())
+	case ty.MemoryType() != nil:
+		memory := extern.Memory()
+		if memory == nil || memory.Type(store).Is64() != ty.MemoryType().Is64() {
+			return false
+		}
+		size := memory.Size(store)
+		hasMax, max := ty.MemoryType().Maximum()
+		return size >= ty.MemoryType().Minimum() && (!hasMax || size <= max)
+	case ty.GlobalType() != nil:
+		global := extern.Global()
+		if global == nil {
+			return false
+		}
+		got := global.Type(store)
+		return got.Content().Kind() == ty.GlobalType().Content().Kind() && got.Mutable() == ty.GlobalType().Mutable()
+	default:
+		table := extern.Table()
+		return table != nil && table.Type(store).Element().Kind() == ty.TableType().Element().Kind()
+	}
+}
+
+func valTypesEqual(a, b []*wasmtime.ValType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Kind() != b[i].Kind() {
+			return false
+		}
+	}
+	return true
+}
+
+// Linker defines the imports of a module on top of WASI and [HostModule].
+type Linker func(store *wasmtime.Store, linker *wasmtime.Linker) error
+
+// newLinker defines the imports of a call: WASI, [HostModule],
+// [AssemblyScriptModule] and those of [link].
+func newLinker(store *wasmtime.Store, env *wasiEnv, host *Host, link Linker) (*wasmtime.Linker, error) {
+	linker := wasmtime.NewLinker(SharedEngine())
+	if err := defineWasi(linker, env); err != nil {
+		return nil, fmt.Errorf("defining wasi functions: %v", err)
+	}
+	if err := DefineHostModule(linker, host); err != nil {
+		return nil, fmt.Errorf("defining host functions: %v", err)
+	}
+	if err := defineAssemblyScript(linker, host); err != nil {
+		return nil, fmt.Errorf("defining assemblyscript functions: %v", err)
+	}
+	if link != nil {
+		if err := link(store, linker); err != nil {
+			return nil, err
+		}
+	}
+	linker.AllowShadowing(true)
+	return linker, nil
+}
+
+type stdoutResultJson struct {
+	Result   []byte `json:"result"`
+	Success  bool   `json:"success"`
+	EndState []byte `json:"endState"`
+	Error    string `json:"error"`
+}
+
+// Run instantiates [module] in a fresh store, so no state is shared between
+// calls, and calls its _start function. Contracts either use the host
+// functions or receive the call as JSON on stdin and print their result as
+// JSON on stdout. If the contract ran but failed, the result holding the fuel
+// consumed and the console output is returned with the error.
+func Run(params ExecParams, module *wasmtime.Module, link Linker) (*ExecResult, error) {
+	host := NewHost(HostParams{
+		Input:     params.Payload,
+		Actor:     params.Actor,
+		MaxResult: params.MaxOutput,
+		State:     params.CurrentState,
+		Storage:   params.Storage,
+		Value:     params.Value,
+		Chain:     params.Chain,
+	})
+	env := newWasiEnv(params, host)
+
+	store := wasmtime.NewStore(SharedEngine())
+	defer store.Close()
+	host.SetFuelMeter(storeFuel{store})
+
+	linker, err := newLinker(store, env, host, link)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := linker.Instantiate(store, module)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating user code instance: %v", err)
+	}
+	mainFunc := instance.GetFunc(store, "_start")
+	if mainFunc == nil {
+		return nil, fmt.Errorf("user code does not export _start")
+	}
+
+	store.Limiter(params.MaxMemory, -1, -1, -1, -1)
+
+	err = store.SetFuel(params.MaxFuel)
+	if err != nil {
+		return nil, fmt.Errorf("setting fuel: %v", err)
+	}
+
+	if params.MaxTime > 0 {
+		// +1 as the current epoch is already partially elapsed
+		store.SetEpochDeadline(uint64(params.MaxTime/epochTick) + 1)
+	} else {
+		store.SetEpochDeadline(noEpochDeadline)
+	}
+
+	startTime := time.Now()
+	_, err = mainFunc.Call(store)
+	execTime := time.Since(startTime)
+	// a nested call timing out stops the whole call tree
+	if isInterrupted(err) || errors.Is(host.ChainError(), ErrTimeout) {
+		return nil, ErrTimeout
+	}
+	if env.exited {
+		// proc_exit(0) stops the contract like returning from _start
+		err = nil
+	}
+
+	// the fuel left can be read after a trap too
+	fuelAfter, fuelErr := store.GetFuel()
+	if fuelErr != nil {
+		return nil, fmt.Errorf("getting fuel after execution: %v", fuelErr)
+	}
+	result := &ExecResult{
+		FuelConsumed: params.MaxFuel - fuelAfter,
+		TimeTaken:    execTime,
+		StdErr:       env.stderr.Bytes(),
+	}
+	if err := host.AbortError(); err != nil {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("calling user code main function: %w", err)
+	}
+
+	var endState []byte
+	if host.Used() {
+		endState = host.State()
+		result.Result = host.Result()
+		result.StorageWrites = host.Writes()
+		result.SelfDestruct = host.Beneficiary()
+	} else {
+		stdoutBytes := env.stdout.Bytes()
+
+		var stdoutResult stdoutResultJson
+		err = json.Unmarshal(stdoutBytes, &stdoutResult)
+		if err != nil {
+			return result, fmt.Errorf("unmarshalling stdout: %v", err)
+		}
+		if !stdoutResult.Success {
+			return result, fmt.Errorf("execution failed: %s", string(stdoutBytes))
+		}
+		endState = stdoutResult.EndState
+		result.Result = stdoutResult.Result
+	}
+
+	if !bytes.Equal(endState, params.CurrentState) {
+		result.UpdatedState = &endState
+	}
+	return result, nil
+}
+
+// storeFuel charges the metered host functions to the fuel of the store, like
+// executing instructions would.
+type storeFuel struct {
+	store *wasmtime.Store
+}
+
+func (f storeFuel) Remaining() (uint64, error) {
+	return f.store.GetFuel()
+}
+
+func (f storeFuel) Consume(fuel uint64) error {
+	remaining, err := f.store.GetFuel()
+	if err != nil {
+		return err
+	}
+	if remaining < fuel {
+		return ErrOutOfFuel
+	}
+	return f.store.SetFuel(remaining - fuel)
+}
+
+func isInterrupted(err error) bool {
+	var trap *wasmtime.Trap
+	if !errors.As(err, &trap) {
+		return false
+	}
+	code := trap.Code()
+	return code != nil && *code == wasmtime.Interrupt
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
 )
 
 //go:embed javy_provider_1.4.0.wasm
@@ -17,7 +18,7 @@ var javyProviderCompiled *[]byte = nil
 
 var compileWasmMutex = sync.Mutex{}
 
-func getCwasmBytes() (*[]byte, error) {
+func getCwasmBytes(engine *wasmtime.Engine) (*[]byte, error) {
 	if javyProviderCompiled != nil {
 		return javyProviderCompiled, nil
 	}
@@ -33,11 +34,6 @@ func getCwasmBytes() (*[]byte, error) {
 	_, err = os.Stat(cachedFilePath)
 
 	if err != nil && os.IsNotExist(err) {
-		config := wasmtime.NewConfig()
-		config.SetConsumeFuel(true)
-
-		engine := wasmtime.NewEngineWithConfig(config)
-
 		javyProviderModule, err := wasmtime.NewModule(engine, javyProviderWasm)
 		if err != nil {
 			return nil, fmt.Errorf("instantiating javy provider module: %v", err)
@@ -71,6 +67,6 @@ func getCwasmCachePath() (string, error) {
 		return "", fmt.Errorf("getting user cache dir: %v", err)
 	}
 
-	result := filepath.Join(cacheDir, "javy_provider.cwasm")
+	result := filepath.Join(cacheDir, fmt.Sprintf("javy_provider.%d.cwasm", execution.EngineConfigVersion))
 	return result, nil
 }
@@ -0,0 +1,58 @@
+package v1javy
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+)
+
+// providerModuleName is the import module of the javy provider
+const providerModuleName = "javy_quickjs_provider_v1"
+
+// The javy provider is compiled once per process for the shared engine.
+var (
+	providerModuleOnce sync.Once
+	providerModule     *wasmtime.Module
+	providerModuleErr  error
+)
+
+// getProviderModule returns the javy provider module compiled for
+// [execution.SharedEngine].
+func getProviderModule() (*wasmtime.Module, error) {
+	providerModuleOnce.Do(func() {
+		engine := execution.SharedEngine()
+
+		compiledLib, err := getCwasmBytes(engine)
+		if err != nil {
+			providerModuleErr = fmt.Errorf("getting javy provider compiled wasm: %v", err)
+			return
+		}
+
+		libraryModule, err := wasmtime.NewModuleDeserialize(engine, *compiledLib)
+		if err != nil {
+			cwasmCachePath, _ := getCwasmCachePath()
+			log.Printf("Library size: %d", len(*compiledLib))
+			providerModuleErr = fmt.Errorf("instantiating javy library module (consider cleaning up %s): %v", cwasmCachePath, err)
+			return
+		}
+		providerModule = libraryModule
+	})
+	return providerModule, providerModuleErr
+}
+
+// linkProvider instantiates the javy provider in [store] so user modules can
+// import it.
+func linkProvider(store *wasmtime.Store, linker *wasmtime.Linker) error {
+	libraryModule, err := getProviderModule()
+	if err != nil {
+		return err
+	}
+	libraryInstance, err := linker.Instantiate(store, libraryModule)
+	if err != nil {
+		return fmt.Errorf("instantiating javy library instance: %v", err)
+	}
+	return linker.DefineInstance(store, providerModuleName, libraryInstance)
+}
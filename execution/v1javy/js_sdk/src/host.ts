@@ -0,0 +1,170 @@
+// Bindings to the host functions (polyglot_v1). JS can't import wasm functions
+// and Javy only exposes the standard streams, so every call is a single write
+// of a prefixed request to stderr followed by reading the response from stdin.
+// Keep in sync with execution/abi.go.
+
+const enum STDIO {
+    Stdin,
+    Stdout,
+    Stderr,
+}
+
+const ADDRESS_LEN = 33
+
+const HOST_CALL_PREFIX = new Uint8Array([0x0, 0x70, 0x76, 0x31]) // "\0pv1"
+
+const enum HostCall {
+    Input = 1,
+    Actor,
+    SetResult,
+    StorageRead,
+    StorageWrite,
+    Abort,
+    Value,
+    Balance,
+    Transfer,
+    Call,
+    Emit,
+    SelfDestruct,
+}
+
+const enum HostCallStatus {
+    OK,
+    NotFound,
+    Failed,
+}
+
+// STATE_KEY holds the serialized contract instance
+export const STATE_KEY = new Uint8Array(0)
+
+export function getInput(): Uint8Array {
+    return hostCall(HostCall.Input, new Uint8Array(0)).value
+}
+
+export function getActor(): Uint8Array {
+    return hostCall(HostCall.Actor, new Uint8Array(0)).value
+}
+
+export function setResult(result: Uint8Array) {
+    hostCall(HostCall.SetResult, result)
+}
+
+// storageRead returns null if the key is not set
+export function storageRead(key: Uint8Array): Uint8Array | null {
+    const { status, value } = hostCall(HostCall.StorageRead, key)
+    return status === HostCallStatus.NotFound ? null : value
+}
+
+export function storageWrite(key: Uint8Array, value: Uint8Array) {
+    hostCall(HostCall.StorageWrite, concatPrefixed(key, value))
+}
+
+// abort stops the call, discarding all its changes
+export function abort(message: string): never {
+    hostCall(HostCall.Abort, new TextEncoder().encode(message))
+    throw new Error("unreachable: the host did not abort")
+}
+
+// getValue returns the amount attached to the call
+export function getValue(): bigint {
+    return readUint64(hostCall(HostCall.Value, new Uint8Array(0)).value)
+}
+
+// getBalance returns the contract balance, including the attached value and
+// minus the transfers made so far
+export function getBalance(): bigint {
+    return readUint64(hostCall(HostCall.Balance, new Uint8Array(0)).value)
+}
+
+// transfer sends amount from the contract balance to the address, which the
+// call must declare as a recipient
+export function transfer(to: Uint8Array, amount: bigint) {
+    if (to.length !== ADDRESS_LEN) {
+        throw Error(`Invalid address length ${to.length}`)
+    }
+    const args = new Uint8Array(ADDRESS_LEN + 8)
+    args.set(to)
+    new DataView(args.buffer).setBigUint64(ADDRESS_LEN, amount, true)
+    hostCall(HostCall.Transfer, args)
+}
+
+// call executes the contract at the address with input, attaching value from
+// the contract balance. The callee gets at most maxFuel, its changes are
+// discarded if it fails and the result is then its error message.
+export function call(to: Uint8Array, input: Uint8Array, value: bigint, maxFuel: bigint): { success: boolean, result: Uint8Array } {
+    if (to.length !== ADDRESS_LEN) {
+        throw Error(`Invalid address length ${to.length}`)
+    }
+    const args = new Uint8Array(ADDRESS_LEN + 16 + input.length)
+    args.set(to)
+    const view = new DataView(args.buffer)
+    view.setBigUint64(ADDRESS_LEN, value, true)
+    view.setBigUint64(ADDRESS_LEN + 8, maxFuel, true)
+    args.set(input, ADDRESS_LEN + 16)
+    const { status, value: result } = hostCall(HostCall.Call, args)
+    return { success: status === HostCallStatus.OK, result }
+}
+
+// emit records an event with a topic of 1 to 32 bytes, indexers can query
+// the events of accepted calls
+export function emit(topic: Uint8Array, data: Uint8Array) {
+    hostCall(HostCall.Emit, concatPrefixed(topic, data))
+}
+
+// selfDestruct deletes the contract once the call succeeds and sends its
+// balance to the beneficiary, which the call must declare as a recipient.
+// Immutable contracts can't destruct.
+export function selfDestruct(beneficiary: Uint8Array) {
+    if (beneficiary.length !== ADDRESS_LEN) {
+        throw Error(`Invalid address length ${beneficiary.length}`)
+    }
+    hostCall(HostCall.SelfDestruct, beneficiary)
+}
+
+// concatPrefixed returns the length of first (u32 little endian), first and
+// second
+function concatPrefixed(first: Uint8Array, second: Uint8Array): Uint8Array {
+    const args = new Uint8Array(4 + first.length + second.length)
+    new DataView(args.buffer).setUint32(0, first.length, true)
+    args.set(first, 4)
+    args.set(second, 4 + first.length)
+    return args
+}
+
+function readUint64(bytes: Uint8Array): bigint {
+    return new DataView(bytes.buffer, bytes.byteOffset, bytes.byteLength).getBigUint64(0, true)
+}
+
+function hostCall(call: HostCall, args: Uint8Array): { status: HostCallStatus, value: Uint8Array } {
+    const request = new Uint8Array(HOST_CALL_PREFIX.length + 1 + args.length)
+    request.set(HOST_CALL_PREFIX)
+    request[HOST_CALL_PREFIX.length] = call
+    request.set(args, HOST_CALL_PREFIX.length + 1)
+    // a request must be a single write, stderr is unbuffered
+    if (Javy.IO.writeSync(STDIO.Stderr, request) !== request.length) {
+        throw Error("Error while calling the host")
+    }
+
+    const response = readAll(STDIO.Stdin)
+    return { status: response[0], value: response.subarray(1) }
+}
+
+function readAll(fd: number): Uint8Array {
+    let buffer = new Uint8Array(1024);
+    let bytesUsed = 0;
+    while (true) {
+        const bytesRead = Javy.IO.readSync(fd, buffer.subarray(bytesUsed));
+        if (bytesRead < 0) {
+            throw Error("Error while reading from file descriptor");
+        }
+        if (bytesRead === 0) {
+            return buffer.subarray(0, bytesUsed);
+        }
+        bytesUsed += bytesRead;
+        if (bytesUsed === buffer.length) {
+            const nextBuffer = new Uint8Array(buffer.length * 2);
+            nextBuffer.set(buffer);
+            buffer = nextBuffer;
+        }
+    }
+}
@@ -1,4 +1,5 @@
-import { Uint8ArrayToBase64, base64ToUint8Array, readStdin, writeStdOut } from "./javy_io";
+import { Uint8ArrayToBase64, base64ToUint8Array } from "./javy_io";
+import { STATE_KEY, abort, call, emit, getActor, getBalance, getInput, getValue, selfDestruct, setResult, storageRead, storageWrite, transfer } from "./host";
 import {
     type deserialize as BorshDeserialize,
     type serialize as BorshSerialize,
@@ -7,6 +8,7 @@ import {
 
 
 export { Uint8ArrayToBase64, base64ToUint8Array };
+export { STATE_KEY, abort, call, emit, getActor, getBalance, getInput, getValue, selfDestruct, setResult, storageRead, storageWrite, transfer };
 
 export abstract class FunctionCallParams {
 }
@@ -22,17 +24,8 @@ export function executeContract<T extends ContractIFace>(
     validate: typeof BorshValidate
 ) {
     try {
-        type incomingMessage = {
-            currentState: string,
-            payload: string,
-            actor: string,
-        }
-
-        const stdinStr = readStdin()
-
-        const input = JSON.parse(stdinStr) as incomingMessage
-        const contractStateBytes: Uint8Array = base64ToUint8Array(input.currentState || "")
-        const payloadBytes: Uint8Array = base64ToUint8Array(input.payload || "")
+        const contractStateBytes: Uint8Array = storageRead(STATE_KEY) || new Uint8Array(0)
+        const payloadBytes: Uint8Array = getInput()
 
         let contractInstance: T;
         if (contractStateBytes.length === 0) {
@@ -47,20 +40,11 @@ export function executeContract<T extends ContractIFace>(
 
         const callParamsDecoded = deserialize(payloadBytes, FunctionCallParams)
 
-        const result = contractInstance.execute(callParamsDecoded, base64ToUint8Array(input.actor || ""))
+        const result = contractInstance.execute(callParamsDecoded, getActor())
 
-        const endState = serialize(contractInstance)
-
-        writeStdOut(JSON.stringify({
-            success: true,
-            endState: Uint8ArrayToBase64(endState),
-            result: Uint8ArrayToBase64(result ? serialize(result) : new Uint8Array(0)),
-        }))
+        storageWrite(STATE_KEY, serialize(contractInstance))
+        setResult(result ? serialize(result) : new Uint8Array(0))
     } catch (e) {
-        writeStdOut(JSON.stringify({
-            success: false,
-            error: String(e) + "\n" + (e as Error).stack,
-        }))
+        abort(String(e) + "\n" + (e as Error).stack)
     }
 }
-
@@ -0,0 +1,65 @@
+package v1javy_test
+
+import (
+	"testing"
+
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+)
+
+// withCustomSection returns a valid copy of [wasm] with a different hash
+func withCustomSection(wasm []byte, name string) []byte {
+	section := append([]byte{byte(len(name))}, name...)
+	result := append([]byte{}, wasm...)
+	result = append(result, 0x0, byte(len(section)))
+	return append(result, section...)
+}
+
+func TestModuleCache(t *testing.T) {
+	exec := v1javy.NewJavyExecWithCacheSize(1)
+
+	params := DEFAULT_PARAMS_LIMITS
+	params.Payload = generateIncrementPayload(1)
+	params.Actor = createActorAddress(1)
+
+	for i := 0; i < 3; i++ {
+		res, err := exec.Execute(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// every call starts from a fresh store
+		if decodeCounterState(t, res) != 1 {
+			t.Fatalf("Expected counter %d, got %d", 1, decodeCounterState(t, res))
+		}
+	}
+
+	stats := exec.ModuleCacheStats()
+	if stats.Misses != 1 || stats.Hits != 2 || stats.Evictions != 0 || stats.Size != 1 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+
+	otherWasm := withCustomSection(testWasmBytes, "other")
+	params.Bytecode = &otherWasm
+	if _, err := exec.Execute(params); err != nil {
+		t.Fatal(err)
+	}
+
+	stats = exec.ModuleCacheStats()
+	if stats.Misses != 2 || stats.Hits != 2 || stats.Evictions != 1 || stats.Size != 1 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+}
+
+func decodeCounterState(t *testing.T, res *execution.ExecResult) uint64 {
+	if res.UpdatedState == nil {
+		t.Fatal("Expected state update")
+	}
+	params := DEFAULT_PARAMS_LIMITS
+	params.CurrentState = *res.UpdatedState
+	params.Payload = generateGetCounterPayload(createActorAddress(1))
+	getRes, err := v1javy.NewJavyExec().Execute(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decodeGetCounterResult(getRes.Result)
+}
@@ -0,0 +1,41 @@
+package v1javy_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+)
+
+func TestDeterminism(t *testing.T) {
+	t.Parallel()
+
+	params := DEFAULT_PARAMS_LIMITS
+	params.Payload = generateIncrementPayload(5)
+	params.Actor = createActorAddress(1)
+	params.Timestamp = 1700000000000
+	params.Seed = []byte("seed")
+
+	var expected *execution.ExecResult
+	for i := 0; i < 3; i++ {
+		// a new executor each time, as on different validators
+		res, err := v1javy.NewJavyExec().Execute(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected == nil {
+			expected = res
+			continue
+		}
+		if res.FuelConsumed != expected.FuelConsumed {
+			t.Fatalf("Expected fuel %d, got %d", expected.FuelConsumed, res.FuelConsumed)
+		}
+		if !bytes.Equal(*res.UpdatedState, *expected.UpdatedState) {
+			t.Fatal("Expected the same end state")
+		}
+		if !bytes.Equal(res.Result, expected.Result) {
+			t.Fatal("Expected the same result")
+		}
+	}
+}
@@ -5,88 +5,76 @@ package v1javy_test
 //go:generate npx ../v1javy/js_sdk/ assets/counters.ts
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 
 	_ "embed"
 
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/near/borsh-go"
+	"github.com/containerman17/avalanche-polyglot-subnet/abi"
+	"github.com/containerman17/avalanche-polyglot-subnet/consts"
 )
 
 //go:embed assets/counters.wasm
 var testWasmBytes []byte
 
-//MySuperCalculator.loadCPU
-
-type loadCPUCallParams struct {
-	n uint16
-}
+// describes assets/counters.ts, keep them in sync
+//
+//go:embed assets/counters.abi.json
+var testABIBytes []byte
 
-const LOAD_CPU_VARIANT = 0x3
+var testABI = mustParseABI(testABIBytes)
 
-func generateLoadCPUPayload(n uint16) []byte {
-	bytes, err := borsh.Serialize(loadCPUCallParams{
-		n: n,
-	})
+func mustParseABI(b []byte) *abi.ABI {
+	a, err := abi.Parse(b)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	return append([]byte{LOAD_CPU_VARIANT}, bytes...)
-}
-
-//MySuperCalculator.increment
-
-const INCREMENT_VARIANT = 0x0
-
-type incrementCallParams struct {
-	Amount uint64
+	return a
 }
 
-func generateIncrementPayload(amount uint64) []byte {
-	bytes, err := borsh.Serialize(incrementCallParams{
-		Amount: amount,
-	})
+func encodeCall(name string, args string) []byte {
+	payload, err := testABI.EncodeCall(name, []byte(args))
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	return append([]byte{INCREMENT_VARIANT}, bytes...)
+	return payload
 }
 
-//MySuperCalculator.getCounter
+//MySuperCalculator.loadCPU
 
-const GET_COUNTER_VARIANT = 0x2
+func generateLoadCPUPayload(n uint16) []byte {
+	return encodeCall("loadCPU", fmt.Sprintf(`{"n": %d}`, n))
+}
 
-type getCounterCallParams struct {
-	User [33]byte
+//MySuperCalculator.increment
+
+func generateIncrementPayload(amount uint64) []byte {
+	return encodeCall("increment", fmt.Sprintf(`{"amount": %d}`, amount))
 }
 
+//MySuperCalculator.getCounter
+
 func generateGetCounterPayload(user []byte) []byte {
-	fixedUser := [33]byte{}
+	fixedUser := codec.Address{}
 	copy(fixedUser[:], user)
-
-	bytes, err := borsh.Serialize(getCounterCallParams{
-		User: fixedUser,
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return append([]byte{GET_COUNTER_VARIANT}, bytes...)
+	return encodeCall("getCounter", fmt.Sprintf(`{"user": %q}`, codec.MustAddressBech32(consts.HRP, fixedUser)))
 }
 
 type getCounterResult struct {
-	Counter uint64
+	Counter uint64 `json:"counter"`
 }
 
 func decodeGetCounterResult(payload []byte) uint64 {
-	result := &getCounterResult{}
-	err := borsh.Deserialize(result, payload)
+	b, err := testABI.DecodeResult("getCounter", payload)
 	if err != nil {
 		log.Fatal(err)
 	}
-
+	result := &getCounterResult{}
+	if err := json.Unmarshal(b, result); err != nil {
+		log.Fatal(err)
+	}
 	return result.Counter
 }
 
@@ -0,0 +1,252 @@
+package v1javy_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+)
+
+// hostContractWat stores its input under "key" and in the state, and returns
+// the previous value of "key"
+const hostContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "storage_read" (func $storage_read (param i32 i32 i32 i32) (result i32)))
+  (import "polyglot_v1" "storage_write" (func $storage_write (param i32 i32 i32 i32)))
+  (import "polyglot_v1" "abort" (func $abort (param i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "key")
+  (data (i32.const 16) "empty input")
+  (func (export "_start")
+    (local $len i32)
+    (local $prev i32)
+    (local.set $len (call $input_size))
+    (if (i32.eqz (local.get $len))
+      (then (call $abort (i32.const 16) (i32.const 11))))
+    (call $input_read (i32.const 1024))
+    (local.set $prev (call $storage_read (i32.const 0) (i32.const 3) (i32.const 2048) (i32.const 1024)))
+    (if (i32.ge_s (local.get $prev) (i32.const 0))
+      (then (call $set_result (i32.const 2048) (local.get $prev))))
+    (call $storage_write (i32.const 0) (i32.const 3) (i32.const 1024) (local.get $len))
+    (call $storage_write (i32.const 0) (i32.const 0) (i32.const 1024) (local.get $len))))
+`
+
+type mapStorage map[string][]byte
+
+func (s mapStorage) Get(key []byte) ([]byte, bool, error) {
+	value, ok := s[string(key)]
+	return value, ok, nil
+}
+
+func hostParams(t *testing.T) execution.ExecParams {
+	bytecode, err := wasmtime.Wat2Wasm(hostContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := DEFAULT_PARAMS_LIMITS
+	params.Bytecode = &bytecode
+	params.Payload = []byte("new")
+	params.Storage = mapStorage{"key": []byte("old")}
+	return params
+}
+
+func TestHostFunctions(t *testing.T) {
+	t.Parallel()
+
+	res, err := v1javy.NewJavyExec().Execute(hostParams(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Result, []byte("old")) {
+		t.Fatalf("Expected result %q, got %q", "old", res.Result)
+	}
+	if !bytes.Equal(res.StorageWrites["key"], []byte("new")) {
+		t.Fatalf("Expected write %q, got %q", "new", res.StorageWrites["key"])
+	}
+	if res.UpdatedState == nil || !bytes.Equal(*res.UpdatedState, []byte("new")) {
+		t.Fatal("Expected the state to be updated")
+	}
+}
+
+func TestHostAbort(t *testing.T) {
+	t.Parallel()
+
+	params := hostParams(t)
+	params.Payload = []byte{}
+
+	_, err := v1javy.NewJavyExec().Execute(params)
+	if !errors.Is(err, execution.ErrAborted) || !strings.Contains(err.Error(), "empty input") {
+		t.Fatalf("Expected abort, got %v", err)
+	}
+}
+
+func TestHostStorageUnavailable(t *testing.T) {
+	t.Parallel()
+
+	params := hostParams(t)
+	params.Storage = nil
+
+	_, err := v1javy.NewJavyExec().Execute(params)
+	if err == nil || !strings.Contains(err.Error(), execution.ErrStorageUnavailable.Error()) {
+		t.Fatalf("Expected storage error, got %v", err)
+	}
+}
+
+// chainContractWat sends 60 to the empty address, calls it with its input,
+// emits the input as an "echo" event and returns the result of the call
+const chainContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "transfer" (func $transfer (param i32 i64)))
+  (import "polyglot_v1" "call" (func $call (param i32 i32 i32 i64 i64) (result i32)))
+  (import "polyglot_v1" "call_result_size" (func $call_result_size (result i32)))
+  (import "polyglot_v1" "call_result_read" (func $call_result_read (param i32)))
+  (import "polyglot_v1" "emit" (func $emit (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 40) "echo")
+  (func (export "_start")
+    (call $transfer (i32.const 0) (i64.const 60))
+    (call $input_read (i32.const 64))
+    (drop (call $call (i32.const 0) (i32.const 64) (call $input_size) (i64.const 0) (i64.const 1000000)))
+    (call $emit (i32.const 40) (i32.const 4) (i32.const 64) (call $input_size))
+    (call $call_result_read (i32.const 1024))
+    (call $set_result (i32.const 1024) (call $call_result_size))))
+`
+
+// echoChain holds [balance] and answers every call with its input.
+type echoChain struct {
+	balance   uint64
+	transfers []uint64
+	maxFuel   uint64
+	events    []string
+}
+
+func (c *echoChain) Balance() (uint64, error) {
+	return c.balance, nil
+}
+
+func (c *echoChain) Transfer(_ codec.Address, amount uint64) error {
+	if amount > c.balance {
+		return execution.ErrInsufficientFunds
+	}
+	c.balance -= amount
+	c.transfers = append(c.transfers, amount)
+	return nil
+}
+
+func (c *echoChain) Call(_ codec.Address, input []byte, _ uint64, maxFuel uint64) (*execution.CallResult, error) {
+	c.maxFuel = maxFuel
+	return &execution.CallResult{Success: true, Result: input, FuelConsumed: 1000}, nil
+}
+
+func (c *echoChain) Emit(topic []byte, data []byte) error {
+	c.events = append(c.events, string(topic)+":"+string(data))
+	return nil
+}
+
+func chainParams(t *testing.T, chain execution.Chain) execution.ExecParams {
+	bytecode, err := wasmtime.Wat2Wasm(chainContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := DEFAULT_PARAMS_LIMITS
+	params.Bytecode = &bytecode
+	params.Payload = []byte("echo")
+	params.Chain = chain
+	return params
+}
+
+func TestHostChain(t *testing.T) {
+	t.Parallel()
+
+	chain := &echoChain{balance: 100}
+	params := chainParams(t, chain)
+	res, err := v1javy.NewJavyExec().Execute(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.transfers) != 1 || chain.transfers[0] != 60 {
+		t.Fatalf("Expected a transfer of 60, got %v", chain.transfers)
+	}
+	if !bytes.Equal(res.Result, []byte("echo")) {
+		t.Fatalf("Expected the call result %q, got %q", "echo", res.Result)
+	}
+	if len(chain.events) != 1 || chain.events[0] != "echo:echo" {
+		t.Fatalf("Expected an echo event, got %v", chain.events)
+	}
+	if chain.maxFuel >= params.MaxFuel {
+		t.Fatalf("Expected the call fuel to be bounded by the fuel left, got %d", chain.maxFuel)
+	}
+	if minFuel := uint64(execution.TransferFuel + execution.CallFuel + 1000 + execution.EventFuel); res.FuelConsumed < minFuel {
+		t.Fatalf("Expected at least %d fuel consumed, consumed %d", minFuel, res.FuelConsumed)
+	}
+}
+
+func TestHostTransferInsufficientFunds(t *testing.T) {
+	t.Parallel()
+
+	_, err := v1javy.NewJavyExec().Execute(chainParams(t, &echoChain{balance: 50}))
+	if err == nil || !strings.Contains(err.Error(), execution.ErrInsufficientFunds.Error()) {
+		t.Fatalf("Expected insufficient funds, got %v", err)
+	}
+}
+
+func TestHostTransferOutOfFuel(t *testing.T) {
+	t.Parallel()
+
+	params := chainParams(t, &echoChain{balance: 100})
+	params.MaxFuel = execution.TransferFuel - 1
+
+	_, err := v1javy.NewJavyExec().Execute(params)
+	if err == nil || !strings.Contains(err.Error(), execution.ErrOutOfFuel.Error()) {
+		t.Fatalf("Expected out of fuel, got %v", err)
+	}
+}
+
+// selfDestructContractWat destructs in favor of the address in its input
+const selfDestructContractWat = `
+(module
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "self_destruct" (func $self_destruct (param i32)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (call $input_read (i32.const 0))
+    (call $self_destruct (i32.const 0))))
+`
+
+func TestHostSelfDestruct(t *testing.T) {
+	t.Parallel()
+
+	bytecode, err := wasmtime.Wat2Wasm(selfDestructContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beneficiary := codec.CreateAddress(0, ids.GenerateTestID())
+	params := DEFAULT_PARAMS_LIMITS
+	params.Bytecode = &bytecode
+	params.Payload = beneficiary[:]
+
+	if _, err := v1javy.NewJavyExec().Execute(params); err == nil || !strings.Contains(err.Error(), execution.ErrChainUnavailable.Error()) {
+		t.Fatalf("Expected chain unavailable, got %v", err)
+	}
+
+	params.Chain = &echoChain{}
+	res, err := v1javy.NewJavyExec().Execute(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelfDestruct == nil || *res.SelfDestruct != beneficiary {
+		t.Fatalf("Expected a self destruct in favor of %v, got %v", beneficiary, res.SelfDestruct)
+	}
+}
@@ -1,17 +1,21 @@
 package v1javy_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
 	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
 )
 
-var DEFAULT_PARAMS_LIMITS = v1javy.JavyExecParams{
+var DEFAULT_PARAMS_LIMITS = execution.ExecParams{
 	MaxFuel:      10 * 1000 * 1000,
 	MaxTime:      time.Millisecond * 20,
 	MaxMemory:    1024 * 1024 * 100,
+	MaxOutput:    1024 * 1024,
 	Bytecode:     &testWasmBytes,
 	CurrentState: []byte{},
 	Payload:      generateLoadCPUPayload(60),
@@ -39,6 +43,9 @@ func TestMaxFuel(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error")
 	}
+	if errors.Is(err, execution.ErrTimeout) {
+		t.Error("Expected fuel error, got timeout")
+	}
 }
 
 func TestMaxTime(t *testing.T) {
@@ -63,6 +70,28 @@ func TestMaxTime(t *testing.T) {
 		fmt.Printf("Time consumed: %v\n", res.TimeTaken)
 		t.FailNow()
 	}
+	if !errors.Is(err, execution.ErrTimeout) {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+}
+
+func TestMaxTimeInterrupts(t *testing.T) {
+	t.Parallel()
+
+	exec := v1javy.NewJavyExec()
+
+	params := DEFAULT_PARAMS_LIMITS
+	params.MaxFuel = 1 << 62
+	params.Payload = generateLoadCPUPayload(65535)
+
+	startTime := time.Now()
+	_, err := exec.Execute(params)
+	if !errors.Is(err, execution.ErrTimeout) {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+	if elapsed := time.Since(startTime); elapsed > time.Second {
+		t.Errorf("Expected the call to be interrupted, took %v", elapsed)
+	}
 }
 
 func TestMaxMemory(t *testing.T) {
@@ -85,3 +114,26 @@ func TestMaxMemory(t *testing.T) {
 		t.Error("Expected error")
 	}
 }
+
+func TestMaxOutput(t *testing.T) {
+	t.Parallel()
+
+	exec := v1javy.NewJavyExec()
+
+	params := DEFAULT_PARAMS_LIMITS
+	params.Payload = generateGetCounterPayload(createActorAddress(1))
+
+	_, err := exec.Execute(params)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// less than the u64 result
+	params.MaxOutput = 4
+
+	_, err = exec.Execute(params)
+	if err == nil || !strings.Contains(err.Error(), "size limit exceeded") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
 	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
 )
 
@@ -14,10 +15,11 @@ func TestReturn(t *testing.T) {
 	actor2Bytes := createActorAddress(2)
 	actor3Bytes := createActorAddress(3)
 
-	params := v1javy.JavyExecParams{
+	params := execution.ExecParams{
 		MaxFuel:      10 * 1000 * 1000,
 		MaxTime:      time.Millisecond * 20,
 		MaxMemory:    1024 * 1024 * 100,
+		MaxOutput:    1024 * 1024,
 		Bytecode:     &testWasmBytes,
 		CurrentState: []byte{},
 		Payload:      generateIncrementPayload(131313),
@@ -0,0 +1,80 @@
+package v1javy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	hostBytecode, err := wasmtime.Wat2Wasm(hostContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasiBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "wasi_snapshot_preview1" "proc_exit" (func (param i32)))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "env" "f" (func))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bogusHostBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "polyglot_v1" "bogus" (func))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathOpenBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "wasi_snapshot_preview1" "path_open" (func (param i32 i32 i32 i32 i32 i64 i64 i32 i32) (result i32)))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatureBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "polyglot_v1" "input_size" (func (param i32) (result i64)))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "polyglot_v1" "input_size" (memory 1))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStartBytecode, err := wasmtime.Wat2Wasm(`(module (global (export "_start") i32 (i32.const 0)))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exec := v1javy.NewJavyExec()
+	for name, test := range map[string]struct {
+		bytecode []byte
+		err      error
+	}{
+		"javy":           {testWasmBytes, nil},
+		"host":           {hostBytecode, nil},
+		"wasi":           {wasiBytecode, nil},
+		"not wasm":       {[]byte{0x01, 0x02, 0x03}, execution.ErrInvalidModule},
+		"env import":     {envBytecode, execution.ErrImportNotAllowed},
+		"unknown host":   {bogusHostBytecode, execution.ErrImportNotAllowed},
+		"unlinked wasi":  {pathOpenBytecode, execution.ErrImportNotAllowed},
+		"signature":      {signatureBytecode, execution.ErrImportMismatch},
+		"func as memory": {memoryBytecode, execution.ErrImportMismatch},
+		"_start global":  {noStartBytecode, execution.ErrStartNotExported},
+	} {
+		if err := exec.Validate(test.bytecode); !errors.Is(err, test.err) {
+			t.Fatalf("%s: expected %v, got %v", name, test.err, err)
+		}
+	}
+}
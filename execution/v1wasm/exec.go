@@ -0,0 +1,48 @@
+package v1wasm
+
+import (
+	"fmt"
+
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+)
+
+// EngineID identifies this engine in the metadata of the contracts it runs.
+const EngineID uint8 = 2
+
+var _ execution.Engine = (*WasmExec)(nil)
+
+// WasmExec runs self-contained wasm32 modules, as built by Rust, TinyGo or
+// AssemblyScript. They may only import WASI, the host functions and the abort
+// of AssemblyScript, and get the same limits as the other engines. It is safe for concurrent use.
+type WasmExec struct {
+	modules *execution.ModuleCache
+}
+
+func NewWasmExec() *WasmExec {
+	return NewWasmExecWithCacheSize(execution.DefaultModuleCacheSize)
+}
+
+// NewWasmExecWithCacheSize creates an executor keeping at most [cacheSize]
+// compiled user modules.
+func NewWasmExecWithCacheSize(cacheSize int) *WasmExec {
+	return &WasmExec{
+		modules: execution.NewModuleCache(cacheSize),
+	}
+}
+
+func (exec *WasmExec) Execute(params execution.ExecParams) (*execution.ExecResult, error) {
+	module, err := exec.modules.GetOrCompile(execution.SharedEngine(), *params.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating user code module: %v", err)
+	}
+	return execution.Run(params, module, nil)
+}
+
+// Validate checks that [bytecode] is a module this engine can run.
+func (exec *WasmExec) Validate(bytecode []byte) error {
+	module, err := exec.modules.GetOrCompile(execution.SharedEngine(), bytecode)
+	if err != nil {
+		return fmt.Errorf("%w: %v", execution.ErrInvalidModule, err)
+	}
+	return execution.ValidateModule(module, nil)
+}
@@ -0,0 +1,193 @@
+package v1wasm_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1wasm"
+)
+
+var DEFAULT_PARAMS_LIMITS = execution.ExecParams{
+	MaxFuel:      10 * 1000 * 1000,
+	MaxTime:      time.Second,
+	MaxMemory:    1024 * 1024 * 100,
+	MaxOutput:    1024 * 1024,
+	CurrentState: []byte("old"),
+	Payload:      []byte("new"),
+	Actor:        []byte{},
+}
+
+// stateContractWat replaces the state with its input and returns the previous
+// state
+const stateContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "storage_read" (func $storage_read (param i32 i32 i32 i32) (result i32)))
+  (import "polyglot_v1" "storage_write" (func $storage_write (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (call $set_result (i32.const 2048)
+      (call $storage_read (i32.const 0) (i32.const 0) (i32.const 2048) (i32.const 1024)))
+    (call $input_read (i32.const 1024))
+    (call $storage_write (i32.const 0) (i32.const 0) (i32.const 1024) (call $input_size))))
+`
+
+// stdioContractWat prints its result on stdout like the javy contracts do
+const stdioContractWat = `
+(module
+  (import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 64) "{\"success\":true,\"result\":\"aGk=\",\"endState\":\"bmV3\"}")
+  (func (export "_start")
+    (i32.store (i32.const 0) (i32.const 64))
+    (i32.store (i32.const 4) (i32.const 50))
+    (drop (call $fd_write (i32.const 1) (i32.const 0) (i32.const 1) (i32.const 8)))))
+`
+
+const loopContractWat = `
+(module
+  (func (export "_start")
+    (loop $forever (br $forever))))
+`
+
+// growContractWat grows its memory to 110MB
+const growContractWat = `
+(module
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (if (i32.lt_s (memory.grow (i32.const 1760)) (i32.const 0))
+      (then unreachable))))
+`
+
+func execute(t *testing.T, wat string) (*execution.ExecResult, error) {
+	bytecode, err := wasmtime.Wat2Wasm(wat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := DEFAULT_PARAMS_LIMITS
+	params.Bytecode = &bytecode
+	return v1wasm.NewWasmExec().Execute(params)
+}
+
+func TestExecuteHost(t *testing.T) {
+	t.Parallel()
+
+	res, err := execute(t, stateContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Result, []byte("old")) {
+		t.Fatalf("Expected result %q, got %q", "old", res.Result)
+	}
+	if res.UpdatedState == nil || !bytes.Equal(*res.UpdatedState, []byte("new")) {
+		t.Fatal("Expected the state to be updated")
+	}
+}
+
+func TestExecuteStdio(t *testing.T) {
+	t.Parallel()
+
+	res, err := execute(t, stdioContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Result, []byte("hi")) {
+		t.Fatalf("Expected result %q, got %q", "hi", res.Result)
+	}
+	if res.UpdatedState == nil || !bytes.Equal(*res.UpdatedState, []byte("new")) {
+		t.Fatal("Expected the state to be updated")
+	}
+}
+
+func TestExecuteLimits(t *testing.T) {
+	t.Parallel()
+
+	// the fuel runs out long before the wall-clock limit
+	if _, err := execute(t, loopContractWat); err == nil || errors.Is(err, execution.ErrTimeout) {
+		t.Fatalf("Expected fuel error, got %v", err)
+	}
+	if _, err := execute(t, growContractWat); err == nil {
+		t.Fatal("Expected memory error")
+	}
+}
+
+// exitContractWat sets its result then exits with the given code
+const exitContractWat = `
+(module
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "ok")
+  (func (export "_start")
+    (call $set_result (i32.const 0) (i32.const 2))
+    (call $proc_exit (i32.const %d))
+    unreachable))
+`
+
+// assemblyScriptAbortWat aborts like a failed AssemblyScript assertion, the
+// strings are UTF-16 preceded by their length in bytes
+const assemblyScriptAbortWat = `
+(module
+  (import "env" "abort" (func $abort (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 4) "\06\00\00\00h\00i\00!\00")
+  (data (i32.const 28) "\08\00\00\00a\00.\00t\00s\00")
+  (func (export "_start")
+    (call $abort (i32.const 8) (i32.const 32) (i32.const 3) (i32.const 7))))
+`
+
+func TestExecuteExit(t *testing.T) {
+	t.Parallel()
+
+	res, err := execute(t, fmt.Sprintf(exitContractWat, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Result, []byte("ok")) {
+		t.Fatalf("Expected result %q, got %q", "ok", res.Result)
+	}
+
+	if _, err := execute(t, fmt.Sprintf(exitContractWat, 1)); err == nil {
+		t.Fatal("Expected exit error")
+	}
+}
+
+func TestExecuteAssemblyScriptAbort(t *testing.T) {
+	t.Parallel()
+
+	_, err := execute(t, assemblyScriptAbortWat)
+	if !errors.Is(err, execution.ErrAborted) || !strings.Contains(err.Error(), "hi! at a.ts:3:7") {
+		t.Fatalf("Expected abort error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	javyBytecode, err := wasmtime.Wat2Wasm(`(module
+  (import "javy_quickjs_provider_v1" "canonical_abi_realloc" (func (param i32 i32 i32 i32) (result i32)))
+  (func (export "_start")))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdioBytecode, err := wasmtime.Wat2Wasm(stdioContractWat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exec := v1wasm.NewWasmExec()
+	if err := exec.Validate(stdioBytecode); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Validate(javyBytecode); !errors.Is(err, execution.ErrImportNotAllowed) {
+		t.Fatalf("Expected import error, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package execution
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bytecodealliance/wasmtime-go/v19"
+)
+
+// WasiModule is the import module of the WASI functions, see [defineWasi].
+const WasiModule = "wasi_snapshot_preview1"
+
+// WASI errno values used by the host functions below
+const (
+	errnoSuccess int32 = 0
+	errnoBadf    int32 = 8
+	errnoFault   int32 = 21
+	errnoInval   int32 = 28
+	errnoSpipe   int32 = 70
+)
+
+const (
+	fdStdin  int32 = 0
+	fdStdout int32 = 1
+	fdStderr int32 = 2
+
+	filetypeCharacterDevice = 2
+
+	clockRealtime  int32 = 0
+	clockMonotonic int32 = 1
+)
+
+// wasiEnv is everything a single call can observe through WASI. It only
+// depends on the call parameters, so every validator sees the same values:
+// the standard streams are kept in memory, the clock is frozen at the block
+// timestamp and random bytes are derived from the seed. There are no
+// environment variables, files or other host resources.
+//
+// Contracts that can't import [HostModule] reach the host functions through
+// the standard streams, see [HostCallPrefix].
+type wasiEnv struct {
+	// stdin is only encoded when the contract reads it, contracts using the
+	// host functions never do.
+	stdin       *bytes.Reader
+	encodeStdin func() ([]byte, error)
+
+	stdout    bytes.Buffer
+	stderr    bytes.Buffer
+	maxOutput int // per stream, writing more traps the call
+
+	host         *Host
+	hostResponse *bytes.Reader
+
+	timestamp uint64 // nanoseconds
+	random    *seededRandom
+
+	exited bool // proc_exit(0) was called
+}
+
+func newWasiEnv(params ExecParams, host *Host) *wasiEnv {
+	return &wasiEnv{
+		encodeStdin: func() ([]byte, error) {
+			return json.Marshal(params)
+		},
+		maxOutput:    params.MaxOutput,
+		host:         host,
+		hostResponse: bytes.NewReader(nil),
+		timestamp:    uint64(params.Timestamp) * 1_000_000,
+		random:       &seededRandom{seed: params.Seed},
+	}
+}
+
+func isOpenFd(fd int32) bool {
+	return fd >= fdStdin && fd <= fdStderr
+}
+
+// seededRandom is a stream of SHA-256(seed || counter) blocks.
+type seededRandom struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+func (r *seededRandom) Read(buf []byte) {
+	for len(buf) > 0 {
+		if len(r.block) == 0 {
+			h := sha256.New()
+			h.Write(r.seed)
+			h.Write(binary.LittleEndian.AppendUint64(nil, r.counter))
+			r.block = h.Sum(nil)
+			r.counter++
+		}
+		n := copy(buf, r.block)
+		buf, r.block = buf[n:], r.block[n:]
+	}
+}
+
+// defineWasi registers the subset of wasi_snapshot_preview1 contracts can
+// import, backed by [env]. There are no arguments, environment variables or
+// preopened directories.
+func defineWasi(linker *wasmtime.Linker, env *wasiEnv) error {
+	funcs := map[string]interface{}{
+		"fd_read": func(caller *wasmtime.Caller, fd, iovs, iovsLen, nread int32) (int32, *wasmtime.Trap) {
+			if fd != fdStdin {
+				return errnoBadf, nil
+			}
+			in := env.hostResponse
+			if !env.host.Used() {
+				if env.stdin == nil {
+					stdin, err := env.encodeStdin()
+					if err != nil {
+						return errnoInval, wasmtime.NewTrap(fmt.Sprintf("encoding stdin: %v", err))
+					}
+					env.stdin = bytes.NewReader(stdin)
+				}
+				in = env.stdin
+			}
+			return forEachIovec(caller, iovs, iovsLen, nread, func(buf []byte) (int, bool) {
+				n, _ := in.Read(buf)
+				return n, n == len(buf)
+			}), nil
+		},
+		"fd_write": func(caller *wasmtime.Caller, fd, iovs, iovsLen, nwritten int32) (int32, *wasmtime.Trap) {
+			var out *bytes.Buffer
+			switch fd {
+			case fdStdout:
+				out = &env.stdout
+			case fdStderr:
+				if request := env.hostCallRequest(caller, iovs, iovsLen); request != nil {
+					return env.hostCall(caller, request, nwritten)
+				}
+				out = &env.stderr
+			default:
+				return errnoBadf, nil
+			}
+			var trap *wasmtime.Trap
+			errno := forEachIovec(caller, iovs, iovsLen, nwritten, func(buf []byte) (int, bool) {
+				if out.Len()+len(buf) > env.maxOutput {
+					trap = wasmtime.NewTrap("output size limit exceeded")
+					return 0, false
+				}
+				out.Write(buf)
+				return len(buf), true
+			})
+			return errno, trap
+		},
+		"fd_close": func(fd int32) int32 {
+			if !isOpenFd(fd) {
+				return errnoBadf
+			}
+			return errnoSuccess
+		},
+		"fd_fdstat_get": func(caller *wasmtime.Caller, fd, statPtr int32) int32 {
+			if !isOpenFd(fd) {
+				return errnoBadf
+			}
+			stat, ok := MemorySlice(caller, statPtr, 24)
+			if !ok {
+				return errnoFault
+			}
+			clear(stat)
+			stat[0] = filetypeCharacterDevice
+			// all rights, there is nothing behind the streams to protect
+			binary.LittleEndian.PutUint64(stat[8:], ^uint64(0))
+			return errnoSuccess
+		},
+		"fd_seek": func(fd int32, _ int64, _ int32, _ int32) int32 {
+			if !isOpenFd(fd) {
+				return errnoBadf
+			}
+			return errnoSpipe
+		},
+		"proc_exit": func(code int32) *wasmtime.Trap {
+			// trapping is the only way to stop the contract
+			if code == 0 {
+				env.exited = true
+				return wasmtime.NewTrap("exit")
+			}
+			return wasmtime.NewTrap("exit with non-zero code")
+		},
+		"clock_time_get": func(caller *wasmtime.Caller, clockID int32, _ int64, timePtr int32) int32 {
+			if clockID != clockRealtime && clockID != clockMonotonic {
+				return errnoInval
+			}
+			if !PutUint64(caller, timePtr, env.timestamp) {
+				return errnoFault
+			}
+			return errnoSuccess
+		},
+		"random_get": func(caller *wasmtime.Caller, bufPtr, bufLen int32) int32 {
+			buf, ok := MemorySlice(caller, bufPtr, bufLen)
+			if !ok {
+				return errnoFault
+			}
+			env.random.Read(buf)
+			return errnoSuccess
+		},
+		"args_get": func(int32, int32) int32 {
+			return errnoSuccess
+		},
+		"args_sizes_get": func(caller *wasmtime.Caller, countPtr, sizePtr int32) int32 {
+			if !PutUint32(caller, countPtr, 0) || !PutUint32(caller, sizePtr, 0) {
+				return errnoFault
+			}
+			return errnoSuccess
+		},
+		"fd_prestat_get": func(int32, int32) int32 {
+			return errnoBadf
+		},
+		"fd_prestat_dir_name": func(int32, int32, int32) int32 {
+			return errnoBadf
+		},
+		"sched_yield": func() int32 {
+			return errnoSuccess
+		},
+		"environ_get": func(int32, int32) int32 {
+			return errnoSuccess
+		},
+		"environ_sizes_get": func(caller *wasmtime.Caller, countPtr, sizePtr int32) int32 {
+			if !PutUint32(caller, countPtr, 0) || !PutUint32(caller, sizePtr, 0) {
+				return errnoFault
+			}
+			return errnoSuccess
+		},
+	}
+	for name, fn := range funcs {
+		if err := linker.FuncWrap(WasiModule, name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hostCallRequest returns the write to stderr if it is a host call request,
+// nil otherwise. Stderr is unbuffered, so a request is never split.
+func (env *wasiEnv) hostCallRequest(caller *wasmtime.Caller, iovs, iovsLen int32) []byte {
+	var data []byte
+	for i := int32(0); i < iovsLen; i++ {
+		iovec, ok := MemorySlice(caller, iovs+i*8, 8)
+		if !ok {
+			return nil
+		}
+		buf, ok := MemorySlice(
+			caller,
+			int32(binary.LittleEndian.Uint32(iovec)),
+			int32(binary.LittleEndian.Uint32(iovec[4:])),
+		)
+		if !ok {
+			return nil
+		}
+		data = append(data, buf...)
+	}
+	if !IsHostCall(data) {
+		return nil
+	}
+	return data
+}
+
+func (env *wasiEnv) hostCall(caller *wasmtime.Caller, request []byte, nwritten int32) (int32, *wasmtime.Trap) {
+	response, err := HandleHostCall(env.host, request)
+	if err != nil {
+		return errnoInval, wasmtime.NewTrap(err.Error())
+	}
+	env.hostResponse = bytes.NewReader(response)
+	if !PutUint32(caller, nwritten, uint32(len(request))) {
+		return errnoFault, nil
+	}
+	return errnoSuccess, nil
+}
+
+// forEachIovec calls [fn] for every buffer of an iovec array until it
+// reports a short transfer, then stores the total length at [totalPtr].
+func forEachIovec(caller *wasmtime.Caller, iovs, iovsLen, totalPtr int32, fn func([]byte) (int, bool)) int32 {
+	total := uint32(0)
+	for i := int32(0); i < iovsLen; i++ {
+		iovec, ok := MemorySlice(caller, iovs+i*8, 8)
+		if !ok {
+			return errnoFault
+		}
+		buf, ok := MemorySlice(
+			caller,
+			int32(binary.LittleEndian.Uint32(iovec)),
+			int32(binary.LittleEndian.Uint32(iovec[4:])),
+		)
+		if !ok {
+			return errnoFault
+		}
+		n, more := fn(buf)
+		total += uint32(n)
+		if !more {
+			break
+		}
+	}
+	if !PutUint32(caller, totalPtr, total) {
+		return errnoFault
+	}
+	return errnoSuccess
+}
@@ -6,6 +6,9 @@ package genesis
 import "errors"
 
 var (
-	ErrInvalidHRP    = errors.New("invalid HRP")
-	ErrInvalidTarget = errors.New("invalid target")
+	ErrInvalidHRP                = errors.New("invalid HRP")
+	ErrInvalidTarget             = errors.New("invalid target")
+	ErrInvalidFuelPerComputeUnit = errors.New("invalid fuel per compute unit")
+	ErrInvalidMaxBytecodeSize    = errors.New("invalid max bytecode size")
+	ErrInvalidBytecodePerUnit    = errors.New("invalid bytecode bytes per compute unit")
 )
@@ -57,6 +57,19 @@ type Genesis struct {
 	StorageKeyWriteUnits      uint64 `json:"storageKeyWriteUnits"`
 	StorageValueWriteUnits    uint64 `json:"storageValueWriteUnits"` // per chunk
 
+	// Contract Execution Parameters
+	ContractFuelPerComputeUnit uint64 `json:"contractFuelPerComputeUnit"`
+	ContractMaxFuel            uint64 `json:"contractMaxFuel"` // per call
+	ContractMaxCallDepth       uint8  `json:"contractMaxCallDepth"`
+	ContractMaxBytecodeSize    uint64 `json:"contractMaxBytecodeSize"` // bytes, up to 128kb
+
+	// ContractBytecodeBytesPerComputeUnit prices the validation, and so the
+	// compilation, of the bytecode of new contracts, upgrades and code
+	ContractBytecodeBytesPerComputeUnit uint64 `json:"contractBytecodeBytesPerComputeUnit"`
+
+	// Contract Storage Deposit Parameters
+	ContractStorageDepositPerChunk uint64 `json:"contractStorageDepositPerChunk"` // returned to the contract when its state shrinks
+
 	// Allocates
 	CustomAllocation []*CustomAllocation `json:"customAllocation"`
 }
@@ -94,6 +107,19 @@ func Default() *Genesis {
 		StorageValueAllocateUnits: 5,
 		StorageKeyWriteUnits:      10,
 		StorageValueWriteUnits:    3,
+
+		// Contract Execution Parameters
+		ContractFuelPerComputeUnit: 10_000,
+		ContractMaxFuel:            10_000_000,
+		ContractMaxCallDepth:       8,
+		ContractMaxBytecodeSize:    128 * 1024,
+
+		ContractBytecodeBytesPerComputeUnit: 1_024,
+
+		// Contract Storage Deposit Parameters
+		//
+		// TODO: tune this
+		ContractStorageDepositPerChunk: 1_000,
 	}
 }
 
@@ -114,6 +140,15 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 	if err := g.StateBranchFactor.Valid(); err != nil {
 		return err
 	}
+	if g.ContractFuelPerComputeUnit == 0 {
+		return ErrInvalidFuelPerComputeUnit
+	}
+	if g.ContractMaxBytecodeSize == 0 || g.ContractMaxBytecodeSize > storage.MaxContractBytecodeSize {
+		return ErrInvalidMaxBytecodeSize
+	}
+	if g.ContractBytecodeBytesPerComputeUnit == 0 {
+		return ErrInvalidBytecodePerUnit
+	}
 
 	supply := uint64(0)
 	for _, alloc := range g.CustomAllocation {
@@ -96,6 +96,30 @@ func (r *Rules) GetStorageValueWriteUnits() uint64 {
 	return r.g.StorageValueWriteUnits
 }
 
+func (r *Rules) GetContractFuelPerComputeUnit() uint64 {
+	return r.g.ContractFuelPerComputeUnit
+}
+
+func (r *Rules) GetContractMaxFuel() uint64 {
+	return r.g.ContractMaxFuel
+}
+
+func (r *Rules) GetContractMaxCallDepth() uint8 {
+	return r.g.ContractMaxCallDepth
+}
+
+func (r *Rules) GetContractMaxBytecodeSize() uint64 {
+	return r.g.ContractMaxBytecodeSize
+}
+
+func (r *Rules) GetContractBytecodeBytesPerComputeUnit() uint64 {
+	return r.g.ContractBytecodeBytesPerComputeUnit
+}
+
+func (r *Rules) GetContractStorageDepositPerChunk() uint64 {
+	return r.g.ContractStorageDepositPerChunk
+}
+
 func (r *Rules) GetMinUnitPrice() fees.Dimensions {
 	return r.g.MinUnitPrice
 }
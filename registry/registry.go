@@ -12,23 +12,36 @@ import (
 	"github.com/containerman17/avalanche-polyglot-subnet/actions"
 	"github.com/containerman17/avalanche-polyglot-subnet/auth"
 	"github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1wasm"
 )
 
 // Setup types
 func init() {
 	consts.ActionRegistry = codec.NewTypeParser[chain.Action, *warp.Message]()
 	consts.AuthRegistry = codec.NewTypeParser[chain.Auth, *warp.Message]()
+	consts.EngineRegistry = execution.NewRegistry()
 
 	errs := &wrappers.Errs{}
 	errs.Add(
 		// When registering new actions, ALWAYS make sure to append at the end.
 		consts.ActionRegistry.Register((&actions.Transfer{}).GetTypeID(), actions.UnmarshalTransfer, false),
 		consts.ActionRegistry.Register((&actions.CreateContract{}).GetTypeID(), actions.UnmarshalCreateContract, false),
+		consts.ActionRegistry.Register((&actions.CallContract{}).GetTypeID(), actions.UnmarshalCallContract, false),
+		consts.ActionRegistry.Register((&actions.UpgradeContract{}).GetTypeID(), actions.UnmarshalUpgradeContract, false),
+		consts.ActionRegistry.Register((&actions.UploadCode{}).GetTypeID(), actions.UnmarshalUploadCode, false),
+		consts.ActionRegistry.Register((&actions.CreateContractFromCode{}).GetTypeID(), actions.UnmarshalCreateContractFromCode, false),
+		consts.ActionRegistry.Register((&actions.DeleteContract{}).GetTypeID(), actions.UnmarshalDeleteContract, false),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
 		consts.AuthRegistry.Register((&auth.SECP256R1{}).GetTypeID(), auth.UnmarshalSECP256R1, false),
 		consts.AuthRegistry.Register((&auth.BLS{}).GetTypeID(), auth.UnmarshalBLS, false),
+
+		// Engine IDs are stored with the contracts, NEVER reuse one.
+		consts.EngineRegistry.Register(v1javy.EngineID, v1javy.NewJavyExec()),
+		consts.EngineRegistry.Register(v1wasm.EngineID, v1wasm.NewWasmExec()),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -8,9 +8,12 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/fees"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
 	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
 )
 
 type Controller interface {
@@ -20,4 +23,11 @@ type Controller interface {
 	GetBalanceFromState(context.Context, codec.Address) (uint64, error)
 	GetContractBytecodeFromState(context.Context, codec.Address) ([]byte, error)
 	GetContractStateFromState(context.Context, codec.Address) ([]byte, error)
+	GetContractStorageFromState(context.Context, codec.Address, []byte) ([]byte, error)
+	GetContractMetadataFromState(context.Context, codec.Address) (*storage.ContractMetadata, error)
+	GetContractDepositFromState(context.Context, codec.Address) (uint64, error)
+	GetContractEvents(context.Context, codec.Address, []byte, uint64, uint64, *storage.ContractEventCursor, int) ([]*storage.ContractEvent, *storage.ContractEventCursor, error)
+	GetCodeHashes(context.Context, ids.ID, int) ([]*storage.UploadedCode, error)
+	EstimateAction(context.Context, codec.Address, chain.Action) (fees.Dimensions, uint64, uint64, error)
+	SimulateCallContract(context.Context, codec.Address, codec.Address, []byte) (*actions.CallContractOutput, []byte, uint64, error)
 }
@@ -5,4 +5,9 @@ package rpc
 
 import "errors"
 
-var ErrTxNotFound = errors.New("tx not found")
+var (
+	ErrTxNotFound        = errors.New("tx not found")
+	ErrInvalidBlockRange = errors.New("invalid block range")
+	ErrUnknownAction     = errors.New("unknown action")
+	ErrInvalidAction     = errors.New("invalid action")
+)
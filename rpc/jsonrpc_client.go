@@ -5,11 +5,14 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/requester"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
@@ -182,3 +185,146 @@ func (cli *JSONRPCClient) ContractState(ctx context.Context, addr string) ([]byt
 	)
 	return resp.State, err
 }
+
+func (cli *JSONRPCClient) ContractStorage(ctx context.Context, addr string, key []byte) ([]byte, error) {
+	resp := new(ContractStorageReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"contractStorage",
+		&ContractStorageArgs{
+			Address: addr,
+			Key:     key,
+		},
+		resp,
+	)
+	return resp.Value, err
+}
+
+// ContractInfo returns who deployed the contract at [addr], when and with
+// which bytecode and engine.
+func (cli *JSONRPCClient) ContractInfo(ctx context.Context, addr string) (*ContractInfoReply, error) {
+	resp := new(ContractInfoReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"contractInfo",
+		&ContractInfoArgs{
+			Address: addr,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// ContractDeposit returns the amount locked for the state of the contract at
+// [addr] and the deposit per chunk.
+func (cli *JSONRPCClient) ContractDeposit(ctx context.Context, addr string) (uint64, uint64, error) {
+	resp := new(ContractDepositReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"contractDeposit",
+		&ContractDepositArgs{
+			Address: addr,
+		},
+		resp,
+	)
+	return resp.Deposit, resp.Rate, err
+}
+
+// CodeHashes lists up to 1024 uploaded codes, starting from [start]
+// (included).
+func (cli *JSONRPCClient) CodeHashes(ctx context.Context, start ids.ID) ([]*storage.UploadedCode, error) {
+	resp := new(CodeHashesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"codeHashes",
+		&CodeHashesArgs{
+			Start: start,
+		},
+		resp,
+	)
+	return resp.Codes, err
+}
+
+// ContractEvents returns a page of the events emitted by the contract at
+// [addr] in the blocks between [fromHeight] and [toHeight] (included),
+// starting at [cursor] (may be nil). A non-empty [topic] only matches the
+// events with that topic. The cursor of the next page is nil on the last one.
+func (cli *JSONRPCClient) ContractEvents(
+	ctx context.Context,
+	addr string,
+	topic []byte,
+	fromHeight uint64,
+	toHeight uint64,
+	cursor *storage.ContractEventCursor,
+) ([]*storage.ContractEvent, *storage.ContractEventCursor, error) {
+	resp := new(ContractEventsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"contractEvents",
+		&ContractEventsArgs{
+			Address:    addr,
+			Topic:      topic,
+			FromHeight: fromHeight,
+			ToHeight:   toHeight,
+			Cursor:     cursor,
+		},
+		resp,
+	)
+	return resp.Events, resp.Next, err
+}
+
+// SimulateCall executes [payload] against the contract at [addr] as [actor]
+// (may be empty) without issuing a transaction. It returns the result, the
+// console output of the contract and the fuel consumed. The console output and
+// the fuel consumed are also returned if the call fails.
+func (cli *JSONRPCClient) SimulateCall(
+	ctx context.Context,
+	addr string,
+	actor string,
+	payload []byte,
+) ([]byte, []byte, uint64, error) {
+	resp := new(SimulateCallReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"simulateCall",
+		&SimulateCallArgs{
+			Address: addr,
+			Actor:   actor,
+			Payload: payload,
+		},
+		resp,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, resp.StdErr, resp.FuelConsumed, errors.New(resp.Error)
+	}
+	return resp.Result, resp.StdErr, resp.FuelConsumed, nil
+}
+
+// Estimate returns the units and fee of a transaction of [action] signed with
+// ED25519 by [actor], and the fuel consumed if it calls a contract.
+func (cli *JSONRPCClient) Estimate(
+	ctx context.Context,
+	actor string,
+	action chain.Action,
+) (*EstimateReply, error) {
+	p := codec.NewWriter(hconsts.ByteLen+action.Size(), hconsts.NetworkSizeLimit)
+	p.PackByte(action.GetTypeID())
+	action.Marshal(p)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	resp := new(EstimateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"estimate",
+		&EstimateArgs{
+			Actor:  actor,
+			Action: p.Bytes(),
+		},
+		resp,
+	)
+	return resp, err
+}
@@ -9,9 +9,12 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/fees"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
 	"github.com/containerman17/avalanche-polyglot-subnet/consts"
 	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
 )
 
 type JSONRPCServer struct {
@@ -131,3 +134,245 @@ func (j *JSONRPCServer) ContractState(req *http.Request, args *ContractStateArgs
 	reply.State = state
 	return err
 }
+
+type ContractStorageArgs struct {
+	Address string `json:"address"`
+	Key     []byte `json:"key"`
+}
+
+type ContractStorageReply struct {
+	Value []byte `json:"value"`
+}
+
+func (j *JSONRPCServer) ContractStorage(req *http.Request, args *ContractStorageArgs, reply *ContractStorageReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.ContractStorage")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	value, err := j.c.GetContractStorageFromState(ctx, addr, args.Key)
+	if err != nil {
+		return err
+	}
+	reply.Value = value
+	return err
+}
+
+type ContractInfoArgs struct {
+	Address string `json:"address"`
+}
+
+type ContractInfoReply struct {
+	Creator   string `json:"creator"`
+	CodeHash  ids.ID `json:"codeHash"`
+	CreatedAt int64  `json:"createdAt"`
+	EngineID  uint8  `json:"engineID"`
+	Admin     string `json:"admin"` // empty if there is none
+	Immutable bool   `json:"immutable"`
+}
+
+func (j *JSONRPCServer) ContractInfo(req *http.Request, args *ContractInfoArgs, reply *ContractInfoReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.ContractInfo")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	metadata, err := j.c.GetContractMetadataFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	reply.Creator = codec.MustAddressBech32(consts.HRP, metadata.Creator)
+	reply.CodeHash = metadata.CodeHash
+	reply.CreatedAt = metadata.CreatedAt
+	reply.EngineID = metadata.EngineID
+	if metadata.Admin != codec.EmptyAddress {
+		reply.Admin = codec.MustAddressBech32(consts.HRP, metadata.Admin)
+	}
+	reply.Immutable = metadata.Immutable
+	return nil
+}
+
+type ContractDepositArgs struct {
+	Address string `json:"address"`
+}
+
+type ContractDepositReply struct {
+	Deposit uint64 `json:"deposit"`
+	Rate    uint64 `json:"rate"` // per chunk
+}
+
+// ContractDeposit returns the amount locked for the state of a contract.
+func (j *JSONRPCServer) ContractDeposit(req *http.Request, args *ContractDepositArgs, reply *ContractDepositReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.ContractDeposit")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	deposit, err := j.c.GetContractDepositFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	reply.Deposit = deposit
+	reply.Rate = j.c.Genesis().ContractStorageDepositPerChunk
+	return nil
+}
+
+// maxContractEvents bounds the events of a page of a ContractEvents query
+const maxContractEvents = 1024
+
+type ContractEventsArgs struct {
+	Address    string `json:"address"`
+	Topic      []byte `json:"topic"` // optional
+	FromHeight uint64 `json:"fromHeight"`
+	ToHeight   uint64 `json:"toHeight"`
+
+	Cursor *storage.ContractEventCursor `json:"cursor"` // optional
+}
+
+type ContractEventsReply struct {
+	Events []*storage.ContractEvent     `json:"events"`
+	Next   *storage.ContractEventCursor `json:"next"` // empty on the last page
+}
+
+// ContractEvents returns the events emitted by a contract in the accepted
+// blocks between FromHeight and ToHeight (included), in order. Pass the Next
+// cursor returned as Cursor to get the next page.
+func (j *JSONRPCServer) ContractEvents(req *http.Request, args *ContractEventsArgs, reply *ContractEventsReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.ContractEvents")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	if args.FromHeight > args.ToHeight {
+		return ErrInvalidBlockRange
+	}
+	events, next, err := j.c.GetContractEvents(ctx, addr, args.Topic, args.FromHeight, args.ToHeight, args.Cursor, maxContractEvents)
+	if err != nil {
+		return err
+	}
+	reply.Events = events
+	reply.Next = next
+	return nil
+}
+
+// maxCodeHashes bounds the code hashes returned by a CodeHashes query
+const maxCodeHashes = 1024
+
+type CodeHashesArgs struct {
+	Start ids.ID `json:"start"` // optional
+}
+
+type CodeHashesReply struct {
+	Codes []*storage.UploadedCode `json:"codes"`
+}
+
+// CodeHashes lists the code uploaded in accepted blocks, in hash order,
+// starting from Start. Pass the last hash returned to get the next page.
+func (j *JSONRPCServer) CodeHashes(req *http.Request, args *CodeHashesArgs, reply *CodeHashesReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.CodeHashes")
+	defer span.End()
+
+	codes, err := j.c.GetCodeHashes(ctx, args.Start, maxCodeHashes)
+	if err != nil {
+		return err
+	}
+	reply.Codes = codes
+	return nil
+}
+
+type SimulateCallArgs struct {
+	Address string `json:"address"`
+	Actor   string `json:"actor"` // optional
+	Payload []byte `json:"payload"`
+}
+
+type SimulateCallReply struct {
+	Result       []byte          `json:"result"`
+	Events       []actions.Event `json:"events"`
+	StdErr       []byte          `json:"stdErr"`
+	FuelConsumed uint64          `json:"fuelConsumed"`
+	Error        string          `json:"error"` // set if the call failed, with its StdErr and FuelConsumed
+}
+
+// SimulateCall executes a contract against the latest state without issuing a
+// transaction. Any state changes made by the contract are discarded.
+func (j *JSONRPCServer) SimulateCall(req *http.Request, args *SimulateCallArgs, reply *SimulateCallReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.SimulateCall")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	actor := codec.EmptyAddress
+	if len(args.Actor) > 0 {
+		actor, err = codec.ParseAddressBech32(consts.HRP, args.Actor)
+		if err != nil {
+			return err
+		}
+	}
+
+	output, stdErr, fuelConsumed, err := j.c.SimulateCallContract(ctx, actor, addr, args.Payload)
+	reply.StdErr = stdErr
+	reply.FuelConsumed = fuelConsumed
+	if err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+	reply.Result = output.Result
+	reply.Events = output.Events
+	return nil
+}
+
+type EstimateArgs struct {
+	Actor  string `json:"actor"`
+	Action []byte `json:"action"` // type ID followed by the marshalled action
+}
+
+type EstimateReply struct {
+	Units        fees.Dimensions `json:"units"`
+	Fee          uint64          `json:"fee"`          // at the current unit prices
+	FuelConsumed uint64          `json:"fuelConsumed"` // by contract calls, to use as MaxFuel
+}
+
+// Estimate returns the units and fee of a transaction of an unsigned action,
+// so its max fee can be set tightly. Contract calls are executed against the
+// latest state with the access they declare, see [Controller.EstimateAction].
+func (j *JSONRPCServer) Estimate(req *http.Request, args *EstimateArgs, reply *EstimateReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Estimate")
+	defer span.End()
+
+	actor, err := codec.ParseAddressBech32(consts.HRP, args.Actor)
+	if err != nil {
+		return err
+	}
+	p := codec.NewReader(args.Action, hconsts.NetworkSizeLimit)
+	unmarshal, _, ok := consts.ActionRegistry.LookupIndex(p.UnpackByte())
+	if !ok {
+		return ErrUnknownAction
+	}
+	action, err := unmarshal(p, nil)
+	if err != nil {
+		return err
+	}
+	if !p.Empty() || p.Err() != nil {
+		return ErrInvalidAction
+	}
+
+	units, fee, fuelConsumed, err := j.c.EstimateAction(ctx, actor, action)
+	if err != nil {
+		return err
+	}
+	reply.Units = units
+	reply.Fee = fee
+	reply.FuelConsumed = fuelConsumed
+	return nil
+}
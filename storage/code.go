@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// [codePrefix] + [hash]
+func CodeKey(hash ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = codePrefix
+	copy(k[1:], hash[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], CodeChunks)
+	return
+}
+
+// SetCode stores [bytecode] under its [hash] for the engine [engineID].
+func SetCode(
+	ctx context.Context,
+	mu state.Mutable,
+	hash ids.ID,
+	engineID uint8,
+	bytecode []byte,
+) error {
+	v := make([]byte, 1+len(bytecode))
+	v[0] = engineID
+	copy(v[1:], bytecode)
+	return mu.Insert(ctx, CodeKey(hash), v)
+}
+
+// GetCode returns the engine ID and bytecode stored under [hash], or
+// [ErrCodeNotFound] if it was never uploaded.
+func GetCode(
+	ctx context.Context,
+	im state.Immutable,
+	hash ids.ID,
+) (uint8, []byte, error) {
+	v, err := im.GetValue(ctx, CodeKey(hash))
+	return innerGetCode(v, err)
+}
+
+func innerGetCode(v []byte, err error) (uint8, []byte, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil, ErrCodeNotFound
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(v) == 0 {
+		return 0, nil, ErrCodeNotFound
+	}
+	return v[0], v[1:], nil
+}
+
+// Used to serve RPC queries
+func GetCodeFromState(
+	ctx context.Context,
+	f ReadState,
+	hash ids.ID,
+) (uint8, []byte, error) {
+	values, errs := f(ctx, [][]byte{CodeKey(hash)})
+	return innerGetCode(values[0], errs[0])
+}
+
+// [codeHashPrefix] + [hash]
+func CodeHashKey(hash ids.ID) []byte {
+	k := make([]byte, 1+consts.IDLen)
+	k[0] = codeHashPrefix
+	copy(k[1:], hash[:])
+	return k
+}
+
+// StoreCodeHash indexes code uploaded in an accepted block, so it can be
+// listed.
+func StoreCodeHash(
+	_ context.Context,
+	db database.KeyValueWriter,
+	hash ids.ID,
+	engineID uint8,
+) error {
+	return db.Put(CodeHashKey(hash), []byte{engineID})
+}
+
+// UploadedCode is code uploaded in an accepted block.
+type UploadedCode struct {
+	Hash     ids.ID `json:"hash"`
+	EngineID uint8  `json:"engineID"`
+}
+
+// GetCodeHashes returns up to [limit] uploaded code hashes, in order, starting
+// from [start].
+func GetCodeHashes(
+	_ context.Context,
+	db database.Iteratee,
+	start ids.ID,
+	limit int,
+) ([]*UploadedCode, error) {
+	iter := db.NewIteratorWithStartAndPrefix(CodeHashKey(start), []byte{codeHashPrefix})
+	defer iter.Release()
+
+	codes := []*UploadedCode{}
+	for len(codes) < limit && iter.Next() {
+		code := &UploadedCode{}
+		copy(code.Hash[:], iter.Key()[1:])
+		if len(iter.Value()) > 0 {
+			code.EngineID = iter.Value()[0]
+		}
+		codes = append(codes, code)
+	}
+	return codes, iter.Error()
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// ContractEvent is an event emitted by a contract in an accepted block.
+type ContractEvent struct {
+	Height uint64 `json:"height"`
+	TxID   ids.ID `json:"txId"`
+	Topic  []byte `json:"topic"`
+	Data   []byte `json:"data"`
+}
+
+// [contractEventPrefix] + [contract]
+func contractEventsPrefix(contract codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen)
+	k[0] = contractEventPrefix
+	copy(k[1:], contract[:])
+	return k
+}
+
+// [contractEventPrefix] + [contract] + [height] + [txIndex] + [eventIndex]
+func ContractEventKey(contract codec.Address, height uint64, txIndex uint32, eventIndex uint32) []byte {
+	k := contractEventsPrefix(contract)
+	k = binary.BigEndian.AppendUint64(k, height)
+	k = binary.BigEndian.AppendUint32(k, txIndex)
+	return binary.BigEndian.AppendUint32(k, eventIndex)
+}
+
+// StoreContractEvent stores the event [eventIndex] of the transaction
+// [txIndex] in the block at [height].
+func StoreContractEvent(
+	_ context.Context,
+	db database.KeyValueWriter,
+	contract codec.Address,
+	height uint64,
+	txIndex uint32,
+	eventIndex uint32,
+	txID ids.ID,
+	topic []byte,
+	data []byte,
+) error {
+	p := codec.NewWriter(consts.IDLen+codec.BytesLen(topic)+codec.BytesLen(data), consts.MaxInt)
+	p.PackID(txID)
+	p.PackBytes(topic)
+	p.PackBytes(data)
+	return db.Put(ContractEventKey(contract, height, txIndex, eventIndex), p.Bytes())
+}
+
+// ContractEventCursor is the position of an event, the next page of a
+// [GetContractEvents] query starts there.
+type ContractEventCursor struct {
+	Height     uint64 `json:"height"`
+	TxIndex    uint32 `json:"txIndex"`
+	EventIndex uint32 `json:"eventIndex"`
+}
+
+// GetContractEvents returns the events of [contract] from the blocks between
+// [fromHeight] and [toHeight] (included), in order. A non-empty [topic] only
+// matches the events with that topic. At most [limit] events are returned,
+// starting at [cursor] if not nil, with the cursor of the next one if more
+// match.
+func GetContractEvents(
+	_ context.Context,
+	db database.Iteratee,
+	contract codec.Address,
+	topic []byte,
+	fromHeight uint64,
+	toHeight uint64,
+	cursor *ContractEventCursor,
+	limit int,
+) ([]*ContractEvent, *ContractEventCursor, error) {
+	prefix := contractEventsPrefix(contract)
+	start := binary.BigEndian.AppendUint64(contractEventsPrefix(contract), fromHeight)
+	if cursor != nil && cursor.Height >= fromHeight {
+		start = ContractEventKey(contract, cursor.Height, cursor.TxIndex, cursor.EventIndex)
+	}
+	iter := db.NewIteratorWithStartAndPrefix(start, prefix)
+	defer iter.Release()
+
+	events := []*ContractEvent{}
+	for iter.Next() {
+		key := iter.Key()[len(prefix):]
+		height := binary.BigEndian.Uint64(key)
+		if height > toHeight {
+			break
+		}
+		event := &ContractEvent{Height: height}
+		p := codec.NewReader(iter.Value(), consts.MaxInt)
+		p.UnpackID(true, &event.TxID)
+		p.UnpackBytes(-1, true, &event.Topic)
+		p.UnpackBytes(-1, false, &event.Data)
+		if err := p.Err(); err != nil {
+			return nil, nil, err
+		}
+		if len(topic) > 0 && string(topic) != string(event.Topic) {
+			continue
+		}
+		if len(events) == limit {
+			return events, &ContractEventCursor{
+				Height:     height,
+				TxIndex:    binary.BigEndian.Uint32(key[consts.Uint64Len:]),
+				EventIndex: binary.BigEndian.Uint32(key[consts.Uint64Len+consts.Uint32Len:]),
+			}, nil
+		}
+		events = append(events, event)
+	}
+	return events, nil, iter.Error()
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
@@ -31,6 +32,31 @@ func ContractStateKey(addr codec.Address) (k []byte) {
 	return
 }
 
+// [contractStoragePrefix] + [address] + [key]
+func ContractStorageKey(addr codec.Address, key []byte) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+len(key)+consts.Uint16Len)
+	k[0] = contractStoragePrefix
+	copy(k[1:], addr[:])
+	copy(k[1+codec.AddressLen:], key)
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+len(key):], ContractStorageChunks)
+	return
+}
+
+// [contractMetadataPrefix] + [address]
+func ContractMetadataKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = contractMetadataPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], ContractMetadataChunks)
+	return
+}
+
+// ValidContractStorageKey reports whether [key] can be used in the storage of
+// a contract. The empty key is the contract state.
+func ValidContractStorageKey(key []byte) bool {
+	return len(key) > 0 && len(key) <= MaxContractStorageKeyLen
+}
+
 func GenerateContractAddress(sender codec.Address, discriminator uint16) codec.Address {
 	combinedBytes := make([]byte, 2+codec.AddressLen)
 	copy(combinedBytes, sender[:])
@@ -40,6 +66,11 @@ func GenerateContractAddress(sender codec.Address, discriminator uint16) codec.A
 	return codec.CreateAddress(lconsts.SMARTCONTRACTID, id)
 }
 
+// CreateContract stores the bytecode and initial state of a new contract.
+// Contracts deployed from the code store have no bytecode of their own. The
+// address of a deleted contract can only be reused once its storage is
+// empty, the deposit record counts the chunks left behind, see
+// [DeleteContract].
 func CreateContract(
 	ctx context.Context,
 	mu state.Mutable,
@@ -52,24 +83,36 @@ func CreateContract(
 	bytecodeKey := ContractBytecodeKey(contractAddress)
 	stateKey := ContractStateKey(contractAddress)
 
-	_, err := mu.GetValue(ctx, bytecodeKey)
-	if err == nil {
-		return codec.EmptyAddress, errors.New("contract already exists")
-	} else if !errors.Is(err, database.ErrNotFound) {
-		return codec.EmptyAddress, err
+	if bytecode != nil {
+		_, err := mu.GetValue(ctx, bytecodeKey)
+		if err == nil {
+			return codec.EmptyAddress, errors.New("contract already exists")
+		} else if !errors.Is(err, database.ErrNotFound) {
+			return codec.EmptyAddress, err
+		}
 	}
 
-	_, err = mu.GetValue(ctx, stateKey)
+	_, err := mu.GetValue(ctx, stateKey)
 	if err == nil {
 		return codec.EmptyAddress, errors.New("contract already exists")
 	} else if !errors.Is(err, database.ErrNotFound) {
 		return codec.EmptyAddress, err
 	}
 
-	err = mu.Insert(ctx, bytecodeKey, bytecode)
+	_, chunks, err := getContractDeposit(ctx, mu, contractAddress)
 	if err != nil {
 		return codec.EmptyAddress, err
 	}
+	if chunks > 0 {
+		return codec.EmptyAddress, ErrStorageLeftBehind
+	}
+
+	if bytecode != nil {
+		err = mu.Insert(ctx, bytecodeKey, bytecode)
+		if err != nil {
+			return codec.EmptyAddress, err
+		}
+	}
 
 	err = mu.Insert(ctx, stateKey, initialState)
 	if err != nil {
@@ -79,7 +122,214 @@ func CreateContract(
 	return contractAddress, nil
 }
 
-// Used to serve RPC queries
+// ContractMetadata describes the deployment of a contract.
+type ContractMetadata struct {
+	Creator   codec.Address
+	CodeHash  ids.ID // SHA-256 of the current bytecode
+	CreatedAt int64  // block timestamp, unix ms
+	EngineID  uint8
+
+	// Admin may upgrade the contract in addition to the creator, it is empty
+	// if there is none
+	Admin codec.Address
+	// Immutable contracts can't be upgraded or deleted
+	Immutable bool
+	// Deleted is set on the metadata kept after a deletion that left storage
+	// behind, so the creator can clear it, see [DeleteContract]
+	Deleted bool
+}
+
+// CanUpgrade reports whether [actor] may replace the bytecode of the contract.
+func (m *ContractMetadata) CanUpgrade(actor codec.Address) bool {
+	if m.Immutable {
+		return false
+	}
+	return actor == m.Creator || (m.Admin != codec.EmptyAddress && actor == m.Admin)
+}
+
+const contractMetadataLen = codec.AddressLen + consts.IDLen + consts.Int64Len + consts.Uint8Len +
+	codec.AddressLen + consts.BoolLen + consts.BoolLen
+
+func SetContractMetadata(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	metadata *ContractMetadata,
+) error {
+	p := codec.NewWriter(contractMetadataLen, contractMetadataLen)
+	p.PackAddress(metadata.Creator)
+	p.PackID(metadata.CodeHash)
+	p.PackInt64(metadata.CreatedAt)
+	p.PackByte(metadata.EngineID)
+	p.PackFixedBytes(metadata.Admin[:]) // may be empty
+	p.PackBool(metadata.Immutable)
+	p.PackBool(metadata.Deleted)
+	return mu.Insert(ctx, ContractMetadataKey(addr), p.Bytes())
+}
+
+// GetContractMetadata returns [ErrContractNotFound] if the contract at [addr]
+// has no metadata or was deleted.
+func GetContractMetadata(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (*ContractMetadata, error) {
+	metadata, err := GetContractMetadataOrTombstone(ctx, im, addr)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Deleted {
+		return nil, ErrContractNotFound
+	}
+	return metadata, nil
+}
+
+// GetContractMetadataOrTombstone also returns the metadata of a deleted
+// contract whose storage isn't cleared yet.
+func GetContractMetadataOrTombstone(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (*ContractMetadata, error) {
+	v, err := im.GetValue(ctx, ContractMetadataKey(addr))
+	return innerGetContractMetadata(v, err)
+}
+
+func innerGetContractMetadata(v []byte, err error) (*ContractMetadata, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrContractNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var metadata ContractMetadata
+	p := codec.NewReader(v, contractMetadataLen)
+	p.UnpackAddress(&metadata.Creator)
+	p.UnpackID(true, &metadata.CodeHash)
+	metadata.CreatedAt = p.UnpackInt64(false)
+	metadata.EngineID = p.UnpackByte()
+	admin := make([]byte, codec.AddressLen)
+	p.UnpackFixedBytes(codec.AddressLen, &admin)
+	metadata.Admin = codec.Address(admin)
+	metadata.Immutable = p.UnpackBool()
+	metadata.Deleted = p.UnpackBool()
+	return &metadata, p.Err()
+}
+
+// GetContractBytecode returns the bytecode of the contract at [addr] or
+// [ErrContractNotFound] if no contract was deployed there.
+func GetContractBytecode(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) ([]byte, error) {
+	bytecode, err := im.GetValue(ctx, ContractBytecodeKey(addr))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrContractNotFound
+	}
+	return bytecode, err
+}
+
+func SetContractBytecode(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	bytecode []byte,
+) error {
+	return mu.Insert(ctx, ContractBytecodeKey(addr), bytecode)
+}
+
+func GetContractState(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) ([]byte, error) {
+	contractState, err := im.GetValue(ctx, ContractStateKey(addr))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrContractNotFound
+	}
+	return contractState, err
+}
+
+func SetContractState(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	contractState []byte,
+) error {
+	return mu.Insert(ctx, ContractStateKey(addr), contractState)
+}
+
+// GetContractStorage returns false if [key] is not set in the storage of the
+// contract at [addr].
+func GetContractStorage(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+	key []byte,
+) ([]byte, bool, error) {
+	if !ValidContractStorageKey(key) {
+		return nil, false, ErrInvalidStorageKey
+	}
+	value, err := im.GetValue(ctx, ContractStorageKey(addr, key))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// SetContractStorage sets [key] in the storage of the contract at [addr]. An
+// empty [value] removes the key.
+func SetContractStorage(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	key []byte,
+	value []byte,
+) error {
+	if !ValidContractStorageKey(key) {
+		return ErrInvalidStorageKey
+	}
+	k := ContractStorageKey(addr, key)
+	if len(value) == 0 {
+		return mu.Remove(ctx, k)
+	}
+	return mu.Insert(ctx, k, value)
+}
+
+// DeleteContract removes the bytecode, state and metadata of the contract at
+// [addr]. Its storage can't be listed, the keys must be removed one by one:
+// while the deposit record counts chunks left behind, the metadata is kept
+// as a tombstone for [metadata.Creator] to clear them.
+func DeleteContract(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	metadata *ContractMetadata,
+) error {
+	if err := mu.Remove(ctx, ContractBytecodeKey(addr)); err != nil {
+		return err
+	}
+	if err := mu.Remove(ctx, ContractStateKey(addr)); err != nil {
+		return err
+	}
+	_, chunks, err := getContractDeposit(ctx, mu, addr)
+	if err != nil {
+		return err
+	}
+	if chunks == 0 {
+		return mu.Remove(ctx, ContractMetadataKey(addr))
+	}
+	tombstone := *metadata
+	tombstone.Deleted = true
+	return SetContractMetadata(ctx, mu, addr, &tombstone)
+}
+
+// Used to serve RPC queries. The bytecode of the contracts deployed from the
+// code store is read from there.
 func GetContractBytecodeFromState(
 	ctx context.Context,
 	f ReadState,
@@ -89,7 +339,15 @@ func GetContractBytecodeFromState(
 	values, errs := f(ctx, [][]byte{k})
 
 	if errors.Is(errs[0], database.ErrNotFound) {
-		return []byte{}, nil
+		metadata, err := GetContractMetadataFromState(ctx, f, addr)
+		if errors.Is(err, ErrContractNotFound) {
+			return []byte{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, bytecode, err := GetCodeFromState(ctx, f, metadata.CodeHash)
+		return bytecode, err
 	}
 
 	return values[0], errs[0]
@@ -109,3 +367,34 @@ func GetContractStateFromState(
 
 	return values[0], errs[0]
 }
+
+func GetContractStorageFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+	key []byte,
+) ([]byte, error) {
+	if !ValidContractStorageKey(key) {
+		return nil, ErrInvalidStorageKey
+	}
+	values, errs := f(ctx, [][]byte{ContractStorageKey(addr, key)})
+
+	if errors.Is(errs[0], database.ErrNotFound) {
+		return []byte{}, nil
+	}
+
+	return values[0], errs[0]
+}
+
+func GetContractMetadataFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (*ContractMetadata, error) {
+	values, errs := f(ctx, [][]byte{ContractMetadataKey(addr)})
+	metadata, err := innerGetContractMetadata(values[0], errs[0])
+	if err == nil && metadata.Deleted {
+		return nil, ErrContractNotFound
+	}
+	return metadata, err
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// [contractDepositPrefix] + [address]
+func ContractDepositKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = contractDepositPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], ContractDepositChunks)
+	return
+}
+
+// The deposit record holds the amount locked and the chunks used by the state
+// and storage of the contract.
+const contractDepositLen = consts.Uint64Len * 2
+
+// StateChunks returns the chunks [value] occupies, counted like the storage
+// fees do.
+func StateChunks(value []byte) uint64 {
+	chunks, _ := keys.NumChunks(value)
+	return uint64(chunks)
+}
+
+// GetContractDeposit returns the amount locked for the state of the contract
+// at [addr].
+func GetContractDeposit(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (uint64, error) {
+	deposit, _, err := innerGetContractDeposit(im.GetValue(ctx, ContractDepositKey(addr)))
+	return deposit, err
+}
+
+// Used to serve RPC queries
+func GetContractDepositFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{ContractDepositKey(addr)})
+	deposit, _, err := innerGetContractDeposit(values[0], errs[0])
+	return deposit, err
+}
+
+// getContractDeposit returns the amount locked for the state of the contract
+// at [addr] and the chunks its state uses.
+func getContractDeposit(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (uint64, uint64, error) {
+	return innerGetContractDeposit(im.GetValue(ctx, ContractDepositKey(addr)))
+}
+
+func innerGetContractDeposit(v []byte, err error) (uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(v) != contractDepositLen {
+		return 0, 0, ErrInvalidDeposit
+	}
+	return binary.BigEndian.Uint64(v), binary.BigEndian.Uint64(v[consts.Uint64Len:]), nil
+}
+
+func setContractDeposit(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	deposit uint64,
+	chunks uint64,
+) error {
+	k := ContractDepositKey(addr)
+	if deposit == 0 && chunks == 0 {
+		return mu.Remove(ctx, k)
+	}
+	v := make([]byte, contractDepositLen)
+	binary.BigEndian.PutUint64(v, deposit)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], chunks)
+	return mu.Insert(ctx, k, v)
+}
+
+// UpdateContractDeposit tracks the chunks used by the state of the contract
+// at [addr] when a value goes from [oldChunks] to [newChunks]. [payer] locks
+// [rate] per chunk the state grows by. The deposit of the chunks freed when it
+// shrinks is returned to the balance of the contract, as the chunks may have
+// been paid for by several actors.
+func UpdateContractDeposit(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	payer codec.Address,
+	rate uint64,
+	oldChunks uint64,
+	newChunks uint64,
+) error {
+	if oldChunks == newChunks {
+		return nil
+	}
+	deposit, chunks, err := getContractDeposit(ctx, mu, addr)
+	if err != nil {
+		return err
+	}
+	if newChunks < oldChunks {
+		if oldChunks-newChunks > chunks {
+			return ErrInvalidDeposit
+		}
+		chunks -= oldChunks - newChunks
+		locked, err := smath.Mul64(rate, chunks)
+		if err != nil || locked > deposit {
+			locked = deposit
+		}
+		if err := setContractDeposit(ctx, mu, addr, locked, chunks); err != nil {
+			return err
+		}
+		if deposit == locked {
+			return nil
+		}
+		return AddBalance(ctx, mu, addr, deposit-locked, true)
+	}
+	chunks, err = smath.Add64(chunks, newChunks-oldChunks)
+	if err != nil {
+		return err
+	}
+	required, err := smath.Mul64(rate, chunks)
+	if err != nil {
+		return err
+	}
+	if required > deposit {
+		if err := SubBalance(ctx, mu, payer, required-deposit); err != nil {
+			return err
+		}
+		deposit = required
+	}
+	return setContractDeposit(ctx, mu, addr, deposit, chunks)
+}
@@ -5,4 +5,11 @@ package storage
 
 import "errors"
 
-var ErrInvalidBalance = errors.New("invalid balance")
+var (
+	ErrInvalidBalance    = errors.New("invalid balance")
+	ErrContractNotFound  = errors.New("contract not found")
+	ErrInvalidStorageKey = errors.New("invalid contract storage key")
+	ErrCodeNotFound      = errors.New("code not found")
+	ErrInvalidDeposit    = errors.New("invalid contract deposit")
+	ErrStorageLeftBehind = errors.New("a deleted contract left storage at this address")
+)
@@ -22,9 +22,26 @@ import (
 
 type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 
+// Immutable serves [state.Immutable] reads from [f], to run actions against
+// the latest state in RPC queries.
+func (f ReadState) Immutable() state.Immutable {
+	return readStateImmutable(f)
+}
+
+type readStateImmutable ReadState
+
+func (f readStateImmutable) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	values, errs := f(ctx, [][]byte{key})
+	return values[0], errs[0]
+}
+
 // Metadata
 // 0x0/ (tx)
 //   -> [txID] => timestamp
+// 0x1/ (contract events)
+//   -> [contract|height|tx index|event index] => event
+// 0x2/ (uploaded code)
+//   -> [code hash] => engine ID
 //
 // State
 // / (height) => store in root
@@ -36,10 +53,24 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x3/ (hypersdk-fee)
 // 0x4/ (hypersdk-incoming warp)
 // 0x5/ (hypersdk-outgoing warp)
+// 0x6/ (contract bytecode)
+//   -> [contract] => bytecode
+// 0x7/ (contract state)
+//   -> [contract] => state
+// 0x8/ (contract storage)
+//   -> [contract|key] => value
+// 0x9/ (contract metadata)
+//   -> [contract] => creator, code hash, creation time, engine ID
+// 0xa/ (code)
+//   -> [code hash] => engine ID, bytecode
+// 0xb/ (contract storage deposit)
+//   -> [contract] => deposit|chunks
 
 const (
 	// metaDB
-	txPrefix = 0x0
+	txPrefix            = 0x0
+	contractEventPrefix = 0x1
+	codeHashPrefix      = 0x2
 
 	// stateDB
 	balancePrefix          = 0x0
@@ -50,11 +81,26 @@ const (
 	outgoingWarpPrefix     = 0x5
 	contractBytecodePrefix = 0x6
 	contractStatePrefix    = 0x7
+	contractStoragePrefix  = 0x8
+	contractMetadataPrefix = 0x9
+	codePrefix             = 0xa
+	contractDepositPrefix  = 0xb
 )
 
 const BalanceChunks uint16 = 1
 const ContractBytecodeChunks uint16 = 2048 // 128kb / 64 bytes
 const ContractStateChunks uint16 = 8192    // 512kb / 64 bytes
+const ContractStorageChunks uint16 = 16    // 1kb / 64 bytes
+const ContractMetadataChunks uint16 = 2
+const CodeChunks uint16 = ContractBytecodeChunks + 1 // engine ID + bytecode
+const ContractDepositChunks uint16 = 1
+
+// MaxContractBytecodeSize is the largest bytecode [ContractBytecodeChunks] can
+// hold
+const MaxContractBytecodeSize = uint64(ContractBytecodeChunks) * 64
+
+// MaxContractStorageKeyLen bounds the keys contracts use in their storage
+const MaxContractStorageKeyLen = 64
 
 var (
 	failureByte  = byte(0x0)
@@ -0,0 +1,244 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// built by `go generate ./execution/v1javy_test`
+	countersWasmPath = "../../execution/v1javy_test/assets/counters.wasm"
+
+	callMaxFuel = 10 * 1000 * 1000
+)
+
+func loadCountersWasm(t *testing.T) []byte {
+	bytecode, err := os.ReadFile(countersWasmPath)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("%s not found, run go generate ./execution/v1javy_test", countersWasmPath)
+	}
+	require.NoError(t, err)
+	return bytecode
+}
+
+func (prep *prepeareResult) sendAction(t *testing.T, action chain.Action) *chain.Result {
+	return prep.sendActionAs(t, prep.factory, action)
+}
+
+func (prep *prepeareResult) sendActionAs(t *testing.T, factory chain.AuthFactory, action chain.Action) *chain.Result {
+	parser, err := prep.instance.lcli.Parser(context.Background())
+	require.NoError(t, err)
+	submit, _, _, err := prep.instance.cli.GenerateTransaction(
+		context.Background(),
+		parser,
+		nil,
+		action,
+		factory,
+	)
+	require.NoError(t, err)
+	require.NoError(t, submit(context.Background()))
+
+	results := prep.expectBlk(t, prep.instance)(false)
+	require.Len(t, results, 1)
+	return results[0]
+}
+
+func (prep *prepeareResult) deployContract(t *testing.T, bytecode []byte) codec.Address {
+	prep.deployed++
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: prep.deployed,
+		EngineID:      v1javy.EngineID,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+	require.NoError(t, err)
+	return contractAddr
+}
+
+// callOutput decodes the output of a successful CallContract
+func callOutput(t *testing.T, result *chain.Result) *actions.CallContractOutput {
+	require.True(t, result.Success, string(result.Output))
+	output, err := actions.UnmarshalCallContractOutput(result.Output)
+	require.NoError(t, err)
+	return output
+}
+
+func TestCallContract(t *testing.T) {
+	bytecode := loadCountersWasm(t)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+
+	//increment: variant 0 + borsh u64
+	increment := binary.LittleEndian.AppendUint64([]byte{0x0}, 1337)
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         increment,
+		MaxFuel:         callMaxFuel,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrStateWriteNotDeclared.Error())
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         increment,
+		MaxFuel:         callMaxFuel,
+		WritesState:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+	require.Nil(t, result.Output)
+
+	stateFromChain, err := prep.instance.lcli.ContractState(context.Background(), codec.MustAddressBech32(lconsts.HRP, contractAddr))
+	require.NoError(t, err)
+	require.NotEmpty(t, stateFromChain)
+
+	//getCounter: variant 2 + borsh [33]u8, only reads the state
+	getCounter := append([]byte{0x2}, prep.addr[:]...)
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         getCounter,
+		MaxFuel:         callMaxFuel,
+	})
+	output := callOutput(t, result)
+	require.Len(t, output.Result, 8)
+	require.Equal(t, uint64(1337), binary.LittleEndian.Uint64(output.Result))
+
+	//only the fuel actually consumed is charged
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	maxComputeUnits := (&actions.CallContract{MaxFuel: callMaxFuel}).MaxComputeUnits(rules)
+	require.Greater(t, result.Consumed[fees.Compute], uint64(actions.CallContractComputeUnits))
+	require.Less(t, result.Consumed[fees.Compute], maxComputeUnits)
+}
+
+func TestCallContractMaxFuel(t *testing.T) {
+	bytecode := loadCountersWasm(t)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+
+	//above the genesis limit
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         binary.LittleEndian.AppendUint64([]byte{0x0}, 1),
+		MaxFuel:         genesis.Default().ContractMaxFuel + 1,
+	})
+	require.False(t, result.Success)
+	require.Equal(t, actions.OutputMaxFuelExceeded, result.Output)
+
+	//not enough fuel to finish
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         binary.LittleEndian.AppendUint64([]byte{0x0}, 1),
+		MaxFuel:         1000,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), "all fuel consumed")
+}
+
+func TestCallContractNotFound(t *testing.T) {
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: storage.GenerateContractAddress(prep.addr, 42),
+		Payload:         []byte{0x0},
+		MaxFuel:         callMaxFuel,
+	})
+	require.False(t, result.Success)
+	require.Equal(t, storage.ErrContractNotFound.Error(), string(result.Output))
+}
+
+func TestCallContractTimeout(t *testing.T) {
+	bytecode := loadCountersWasm(t)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+	ctx := context.Background()
+
+	balance, err := prep.instance.lcli.Balance(ctx, prep.addrStr)
+	require.NoError(t, err)
+
+	parser, err := prep.instance.lcli.Parser(ctx)
+	require.NoError(t, err)
+	submit := func(increment uint64) {
+		submit, _, _, err := prep.instance.cli.GenerateTransaction(
+			ctx,
+			parser,
+			nil,
+			&actions.CallContract{
+				ContractAddress: contractAddr,
+				Payload:         binary.LittleEndian.AppendUint64([]byte{0x0}, increment),
+				MaxFuel:         callMaxFuel,
+				WritesState:     true,
+			},
+			prep.factory,
+		)
+		require.NoError(t, err)
+		require.NoError(t, submit(ctx))
+	}
+
+	//screened when it enters the mempool
+	submit(1)
+	maxTime := actions.ContractMaxTime()
+	actions.SetContractMaxTime(time.Nanosecond)
+	defer actions.SetContractMaxTime(maxTime)
+	submit(2)
+
+	//the timed out call is dropped and the block is built without it, the
+	//other one is executed regardless of the wall-clock limit
+	require.NoError(t, prep.instance.vm.Builder().Force(ctx))
+	<-prep.instance.toEngine
+	blk, err := prep.instance.vm.BuildBlock(ctx)
+	require.NoError(t, err)
+	require.Len(t, blk.(*chain.StatelessBlock).Txs, 1)
+	require.Zero(t, prep.instance.vm.Mempool().Len(ctx))
+	require.NoError(t, blk.Verify(ctx))
+	require.NoError(t, prep.instance.vm.SetPreference(ctx, blk.ID()))
+	require.NoError(t, blk.Accept(ctx))
+	results := blk.(*chain.StatelessBlock).Results()
+	require.True(t, results[0].Success, string(results[0].Output))
+
+	//only the executed call was charged, with the deposit of its state
+	actions.SetContractMaxTime(maxTime)
+	newBalance, err := prep.instance.lcli.Balance(ctx, prep.addrStr)
+	require.NoError(t, err)
+	deposit, _, err := prep.instance.lcli.ContractDeposit(ctx, codec.MustAddressBech32(lconsts.HRP, contractAddr))
+	require.NoError(t, err)
+	require.Equal(t, balance-results[0].Fee-deposit, newBalance)
+	res, _, _, err := prep.instance.lcli.SimulateCall(ctx, codec.MustAddressBech32(lconsts.HRP, contractAddr), "", append([]byte{0x2}, prep.addr[:]...))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), binary.LittleEndian.Uint64(res))
+}
+
+func TestCallContractFailureFuel(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(`(module (func (export "_start") unreachable))`)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		MaxFuel:         callMaxFuel,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), "unreachable")
+
+	//a failed call is charged the fuel it consumed, not its budget
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	maxComputeUnits := (&actions.CallContract{MaxFuel: callMaxFuel}).MaxComputeUnits(rules)
+	require.Less(t, result.Consumed[fees.Compute], maxComputeUnits)
+}
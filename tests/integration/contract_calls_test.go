@@ -0,0 +1,138 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyCalleeFuel is the budget the proxy gives to its callee
+const proxyCalleeFuel = 1000000
+
+// proxyContractWat calls the contract at the address starting its input with
+// the rest of the input. It returns the status of the call followed by its
+// result.
+const proxyContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "call" (func $call (param i32 i32 i32 i64 i64) (result i32)))
+  (import "polyglot_v1" "call_result_size" (func $call_result_size (result i32)))
+  (import "polyglot_v1" "call_result_read" (func $call_result_read (param i32)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (local $status i32)
+    (call $input_read (i32.const 0))
+    (local.set $status (call $call (i32.const 0) (i32.const 33) (i32.sub (call $input_size) (i32.const 33)) (i64.const 0) (i64.const 1000000)))
+    (i32.store8 (i32.const 4096) (local.get $status))
+    (call $call_result_read (i32.const 4097))
+    (call $set_result (i32.const 4096) (i32.add (call $call_result_size) (i32.const 1)))))
+`
+
+type contractCallsPrep struct {
+	prepeareResult
+	proxy  codec.Address
+	callee codec.Address
+}
+
+func prepareContractCalls(t *testing.T) *contractCallsPrep {
+	proxyBytecode, err := wasmtime.Wat2Wasm(proxyContractWat)
+	require.NoError(t, err)
+	calleeBytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+
+	prep := &contractCallsPrep{prepeareResult: prepare(t)}
+	prep.proxy = prep.deployContract(t, proxyBytecode)
+	prep.callee = prep.deployContract(t, calleeBytecode)
+	return prep
+}
+
+// callProxy returns the result of the proxy or the error message of a failed
+// call
+func (prep *contractCallsPrep) callProxy(t *testing.T, target codec.Address, payload []byte, callees []actions.Callee) ([]byte, bool) {
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: prep.proxy,
+		Payload:         append(target[:], payload...),
+		MaxFuel:         callMaxFuel,
+		Callees:         callees,
+	})
+	if !result.Success {
+		return result.Output, false
+	}
+	return callOutput(t, result).Result, true
+}
+
+func TestContractCall(t *testing.T) {
+	prep := prepareContractCalls(t)
+	calleeStr := codec.MustAddressBech32(lconsts.HRP, prep.callee)
+	callees := []actions.Callee{{Address: prep.callee, StorageKeys: [][]byte{[]byte("key")}}}
+
+	output, success := prep.callProxy(t, prep.callee, []byte("first"), callees)
+	require.True(t, success, string(output))
+	require.Equal(t, []byte{0}, output)
+
+	output, success = prep.callProxy(t, prep.callee, []byte("second"), callees)
+	require.True(t, success, string(output))
+	require.Equal(t, append([]byte{0}, "first"...), output)
+
+	value, err := prep.instance.lcli.ContractStorage(context.Background(), calleeStr, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), value)
+
+	//simulated calls need no declarations
+	proxyStr := codec.MustAddressBech32(lconsts.HRP, prep.proxy)
+	res, _, _, err := prep.instance.lcli.SimulateCall(context.Background(), proxyStr, "", append(prep.callee[:], "third"...))
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0}, "second"...), res)
+}
+
+func TestContractCallFailureReverted(t *testing.T) {
+	prep := prepareContractCalls(t)
+	calleeStr := codec.MustAddressBech32(lconsts.HRP, prep.callee)
+
+	//the callee fails without its storage key, the caller goes on
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: prep.proxy,
+		Payload:         append(prep.callee[:], "first"...),
+		MaxFuel:         callMaxFuel,
+		Callees:         []actions.Callee{{Address: prep.callee}},
+	})
+	output := callOutput(t, result).Result
+	require.Equal(t, byte(1), output[0])
+	require.Contains(t, string(output[1:]), actions.ErrStorageKeyNotDeclared.Error())
+
+	//the failed callee is charged the fuel it consumed, not the budget the
+	//proxy gave it
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	calleeBudgetUnits := (&actions.CallContract{MaxFuel: proxyCalleeFuel}).MaxComputeUnits(rules)
+	require.Less(t, result.Consumed[fees.Compute], calleeBudgetUnits)
+
+	value, err := prep.instance.lcli.ContractStorage(context.Background(), calleeStr, []byte("key"))
+	require.NoError(t, err)
+	require.Empty(t, value)
+}
+
+func TestContractCallNotDeclared(t *testing.T) {
+	prep := prepareContractCalls(t)
+
+	output, success := prep.callProxy(t, prep.callee, []byte("first"), nil)
+	require.False(t, success)
+	require.Contains(t, string(output), actions.ErrCalleeNotDeclared.Error())
+}
+
+func TestContractCallReentrant(t *testing.T) {
+	prep := prepareContractCalls(t)
+
+	output, success := prep.callProxy(t, prep.proxy, prep.callee[:], []actions.Callee{{Address: prep.proxy}})
+	require.False(t, success)
+	require.Contains(t, string(output), actions.ErrReentrantCall.Error())
+}
@@ -0,0 +1,94 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1wasm"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractFromCode(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	codeHash := utils.ToID(bytecode)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.UploadCode{EngineID: v1wasm.EngineID, Bytecode: bytecode})
+	require.True(t, result.Success, string(result.Output))
+	require.Equal(t, codeHash.String(), string(result.Output))
+
+	//the code is keyed by its hash only
+	result = prep.sendAction(t, &actions.UploadCode{EngineID: v1javy.EngineID, Bytecode: bytecode})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrCodeExists.Error())
+
+	codes, err := prep.instance.lcli.CodeHashes(context.Background(), ids.Empty)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.UploadedCode{{Hash: codeHash, EngineID: v1wasm.EngineID}}, codes)
+
+	//two instances of the same code with their own storage
+	contracts := []codec.Address{}
+	for i, payload := range []string{"first", "second"} {
+		result = prep.sendAction(t, &actions.CreateContractFromCode{
+			CodeHash:      codeHash,
+			InitialState:  []byte{},
+			Discriminator: uint16(i),
+		})
+		require.True(t, result.Success, string(result.Output))
+		contractAddrStr := string(result.Output)
+		contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, contractAddrStr)
+		require.NoError(t, err)
+		contracts = append(contracts, contractAddr)
+
+		info, err := prep.instance.lcli.ContractInfo(context.Background(), contractAddrStr)
+		require.NoError(t, err)
+		require.Equal(t, codeHash, info.CodeHash)
+		require.Equal(t, v1wasm.EngineID, info.EngineID)
+		contractBytecode, err := prep.instance.lcli.ContractBytecode(context.Background(), contractAddrStr)
+		require.NoError(t, err)
+		require.Equal(t, bytecode, contractBytecode)
+
+		result = prep.sendAction(t, &actions.CallContract{
+			ContractAddress: contractAddr,
+			Payload:         []byte(payload),
+			MaxFuel:         callMaxFuel,
+			StorageKeys:     [][]byte{[]byte("key")},
+			Codes:           []ids.ID{codeHash},
+		})
+		require.True(t, result.Success, string(result.Output))
+	}
+	for i, value := range []string{"first", "second"} {
+		stored, err := prep.instance.lcli.ContractStorage(context.Background(), codec.MustAddressBech32(lconsts.HRP, contracts[i]), []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, []byte(value), stored)
+	}
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contracts[0],
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     [][]byte{[]byte("key")},
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrCodeNotDeclared.Error())
+}
+
+func TestContractFromCodeNotFound(t *testing.T) {
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CreateContractFromCode{
+		CodeHash:      ids.GenerateTestID(),
+		InitialState:  []byte{},
+		Discriminator: 1,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), storage.ErrCodeNotFound.Error())
+}
@@ -0,0 +1,278 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// selfDestructContractWat deletes itself in favor of the address in the input
+const selfDestructContractWat = `
+(module
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "self_destruct" (func $self_destruct (param i32)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (call $input_read (i32.const 0))
+    (call $self_destruct (i32.const 0))))
+`
+
+func TestDeleteContract(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	result := prep.sendAction(t, &actions.Transfer{To: prep.addr3, Value: 1_000_000})
+	require.True(t, result.Success, string(result.Output))
+
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+	storageKeys := [][]byte{[]byte("key")}
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("value"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+		Value:           1000,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	result = prep.sendActionAs(t, prep.factory3, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     prep.addr3,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrNotContractCreator.Error())
+
+	result = prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     contractAddr,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrInvalidBeneficiary.Error())
+
+	ctx := context.Background()
+	deposit, _, err := prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.NotZero(t, deposit)
+
+	result = prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     prep.addr2,
+		StorageKeys:     storageKeys,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	_, err = prep.instance.lcli.ContractInfo(ctx, contractAddrStr)
+	require.Error(t, err)
+	contractState, err := prep.instance.lcli.ContractState(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Empty(t, contractState)
+	value, err := prep.instance.lcli.ContractStorage(ctx, contractAddrStr, storageKeys[0])
+	require.NoError(t, err)
+	require.Empty(t, value)
+	balance, err := prep.instance.lcli.Balance(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Zero(t, balance)
+	balance, err = prep.instance.lcli.Balance(ctx, prep.addrStr2)
+	require.NoError(t, err)
+	require.Equal(t, 1000+deposit, balance)
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.False(t, result.Success)
+}
+
+func TestDeleteImmutableContract(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1javy.EngineID,
+		Immutable:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+	require.NoError(t, err)
+
+	result = prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     prep.addr,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrContractImmutable.Error())
+
+	//nor can it destruct
+	bytecode, err = wasmtime.Wat2Wasm(selfDestructContractWat)
+	require.NoError(t, err)
+	result = prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 2,
+		EngineID:      v1javy.EngineID,
+		Immutable:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddr, err = codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+	require.NoError(t, err)
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Recipients:      []codec.Address{prep.addr2},
+		WritesState:     true,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrContractImmutable.Error())
+}
+
+func TestRedeployDeletedContract(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	ctx := context.Background()
+	storageKeys := [][]byte{[]byte("key")}
+	result := prep.sendAction(t, &actions.Transfer{To: prep.addr3, Value: 1_000_000})
+	require.True(t, result.Success, string(result.Output))
+
+	//the initial state tells the redeployments apart from the first deployments
+	deployments := 0
+	deploy := func(discriminator uint16) (codec.Address, bool, string) {
+		deployments++
+		result := prep.sendAction(t, &actions.CreateContract{
+			Bytecode:      bytecode,
+			InitialState:  []byte{byte(deployments)},
+			Discriminator: discriminator,
+			EngineID:      v1javy.EngineID,
+		})
+		contractAddr, _ := codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+		return contractAddr, result.Success, string(result.Output)
+	}
+	deployAndDelete := func(discriminator uint16, deletedKeys [][]byte) codec.Address {
+		contractAddr, ok, output := deploy(discriminator)
+		require.True(t, ok, output)
+		result := prep.sendAction(t, &actions.CallContract{
+			ContractAddress: contractAddr,
+			Payload:         []byte("value"),
+			MaxFuel:         callMaxFuel,
+			StorageKeys:     storageKeys,
+		})
+		require.True(t, result.Success, string(result.Output))
+		result = prep.sendAction(t, &actions.DeleteContract{
+			ContractAddress: contractAddr,
+			Beneficiary:     prep.addr2,
+			StorageKeys:     deletedKeys,
+		})
+		require.True(t, result.Success, string(result.Output))
+		return contractAddr
+	}
+
+	//the storage left behind would show up in the new contract
+	deletedAddr := deployAndDelete(1, nil)
+	_, ok, output := deploy(1)
+	require.False(t, ok)
+	require.Contains(t, output, storage.ErrStorageLeftBehind.Error())
+
+	//the deleted contract can't be called, only its creator can clear it
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: deletedAddr,
+		Payload:         []byte("other"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.False(t, result.Success)
+	require.Equal(t, storage.ErrContractNotFound.Error(), string(result.Output))
+	result = prep.sendActionAs(t, prep.factory3, &actions.DeleteContract{
+		ContractAddress: deletedAddr,
+		Beneficiary:     prep.addr3,
+		StorageKeys:     storageKeys,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrNotContractCreator.Error())
+	result = prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: deletedAddr,
+		Beneficiary:     prep.addr2,
+		StorageKeys:     storageKeys,
+	})
+	require.True(t, result.Success, string(result.Output))
+	_, err = prep.instance.lcli.ContractInfo(ctx, codec.MustAddressBech32(lconsts.HRP, deletedAddr))
+	require.Error(t, err)
+	deposit, _, err := prep.instance.lcli.ContractDeposit(ctx, codec.MustAddressBech32(lconsts.HRP, deletedAddr))
+	require.NoError(t, err)
+	require.Zero(t, deposit)
+	redeployedAddr, ok, output := deploy(1)
+	require.True(t, ok, output)
+	require.Equal(t, deletedAddr, redeployedAddr)
+
+	//once cleared, the address starts over
+	deployAndDelete(2, storageKeys)
+	contractAddr, ok, output := deploy(2)
+	require.True(t, ok, output)
+	value, err := prep.instance.lcli.ContractStorage(ctx, codec.MustAddressBech32(lconsts.HRP, contractAddr), storageKeys[0])
+	require.NoError(t, err)
+	require.Empty(t, value)
+}
+
+func TestContractSelfDestruct(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(selfDestructContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+
+	//destructing writes the contract
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Value:           1000,
+		Recipients:      []codec.Address{prep.addr2},
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrStateWriteNotDeclared.Error())
+
+	//the beneficiary must be a declared recipient
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Value:           1000,
+		WritesState:     true,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrRecipientNotDeclared.Error())
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Value:           1000,
+		Recipients:      []codec.Address{prep.addr2},
+		WritesState:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	ctx := context.Background()
+	_, err = prep.instance.lcli.ContractInfo(ctx, contractAddrStr)
+	require.Error(t, err)
+	bytecode, err = prep.instance.lcli.ContractBytecode(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Empty(t, bytecode)
+	balance, err := prep.instance.lcli.Balance(ctx, prep.addrStr2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), balance)
+}
@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractStorageDeposit(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	ctx := context.Background()
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+	storageKeys := [][]byte{[]byte("key")}
+
+	deposit, rate, err := prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Zero(t, deposit)
+	require.Equal(t, genesis.Default().ContractStorageDepositPerChunk, rate)
+	require.NotZero(t, rate)
+
+	//the actor pays for each chunk the storage grows by
+	call := func(payload []byte) (int64, uint64) {
+		before, err := prep.instance.lcli.Balance(ctx, prep.addrStr)
+		require.NoError(t, err)
+		result := prep.sendAction(t, &actions.CallContract{
+			ContractAddress: contractAddr,
+			Payload:         payload,
+			MaxFuel:         callMaxFuel,
+			StorageKeys:     storageKeys,
+		})
+		require.True(t, result.Success, string(result.Output))
+		after, err := prep.instance.lcli.Balance(ctx, prep.addrStr)
+		require.NoError(t, err)
+		deposit, _, err := prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+		require.NoError(t, err)
+		return int64(before) - int64(result.Fee) - int64(after), deposit
+	}
+
+	paid, deposit := call(bytes.Repeat([]byte{1}, 100)) // 2 chunks
+	require.Equal(t, 2*int64(rate), paid)
+	require.Equal(t, 2*rate, deposit)
+
+	//shrinking returns the deposit of the freed chunk to the contract
+	paid, deposit = call([]byte{2}) // 1 chunk
+	require.Zero(t, paid)
+	require.Equal(t, rate, deposit)
+	contractBalance, err := prep.instance.lcli.Balance(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, rate, contractBalance)
+
+	paid, deposit = call(bytes.Repeat([]byte{3}, 100)) // 2 chunks
+	require.Equal(t, int64(rate), paid)
+	require.Equal(t, 2*rate, deposit)
+
+	paid, deposit = call(bytes.Repeat([]byte{4}, 200)) // 4 chunks
+	require.Equal(t, 2*int64(rate), paid)
+	require.Equal(t, 4*rate, deposit)
+
+	//deleting the contract releases the whole deposit with its balance
+	result := prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     prep.addr2,
+		StorageKeys:     storageKeys,
+	})
+	require.True(t, result.Success, string(result.Output))
+	deposit, _, err = prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Zero(t, deposit)
+	balance, err := prep.instance.lcli.Balance(ctx, prep.addrStr2)
+	require.NoError(t, err)
+	require.Equal(t, 5*rate, balance)
+}
+
+func TestContractDepositOfStorageLeftBehind(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	ctx := context.Background()
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         bytes.Repeat([]byte{1}, 100), // 2 chunks
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     [][]byte{[]byte("key")},
+	})
+	require.True(t, result.Success, string(result.Output))
+	_, rate, err := prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+	require.NoError(t, err)
+
+	//the storage not cleared keeps its deposit locked
+	result = prep.sendAction(t, &actions.DeleteContract{
+		ContractAddress: contractAddr,
+		Beneficiary:     prep.addr2,
+	})
+	require.True(t, result.Success, string(result.Output))
+	deposit, _, err := prep.instance.lcli.ContractDeposit(ctx, contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, 2*rate, deposit)
+	balance, err := prep.instance.lcli.Balance(ctx, prep.addrStr2)
+	require.NoError(t, err)
+	require.Zero(t, balance)
+}
@@ -0,0 +1,163 @@
+package integration_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/stretchr/testify/require"
+)
+
+// emitterContractWat emits its input as a "ping" event
+const emitterContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "emit" (func $emit (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "ping")
+  (func (export "_start")
+    (call $input_read (i32.const 1024))
+    (call $emit (i32.const 0) (i32.const 4) (i32.const 1024) (call $input_size))))
+`
+
+// failingEmitterContractWat emits a "ping" event then fails
+const failingEmitterContractWat = `
+(module
+  (import "polyglot_v1" "emit" (func $emit (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "ping")
+  (func (export "_start")
+    (call $emit (i32.const 0) (i32.const 4) (i32.const 0) (i32.const 0))
+    unreachable))
+`
+
+// floodContractWat calls the contract at the address in its input, then emits
+// CallContractMaxEvents "ping" events
+const floodContractWat = `
+(module
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "call" (func $call (param i32 i32 i32 i64 i64) (result i32)))
+  (import "polyglot_v1" "emit" (func $emit (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 64) "ping")
+  (func (export "_start")
+    (local $i i32)
+    (call $input_read (i32.const 0))
+    (drop (call $call (i32.const 0) (i32.const 33) (i32.const 0) (i64.const 0) (i64.const 1000000)))
+    (loop $emit_all
+      (call $emit (i32.const 64) (i32.const 4) (i32.const 0) (i32.const 0))
+      (local.set $i (i32.add (local.get $i) (i32.const 1)))
+      (br_if $emit_all (i32.lt_u (local.get $i) (i32.const 256))))))
+`
+
+func TestContractEvents(t *testing.T) {
+	emitterBytecode, err := wasmtime.Wat2Wasm(emitterContractWat)
+	require.NoError(t, err)
+	proxyBytecode, err := wasmtime.Wat2Wasm(proxyContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	emitter := prep.deployContract(t, emitterBytecode)
+	proxy := prep.deployContract(t, proxyBytecode)
+	emitterStr := codec.MustAddressBech32(lconsts.HRP, emitter)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: emitter,
+		Payload:         []byte("first"),
+		MaxFuel:         callMaxFuel,
+	})
+	output := callOutput(t, result)
+	require.Equal(t, []actions.Event{{Contract: emitter, Topic: []byte("ping"), Data: []byte("first")}}, output.Events)
+
+	//the events of callees are part of the output
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: proxy,
+		Payload:         append(emitter[:], "second"...),
+		MaxFuel:         callMaxFuel,
+		Callees:         []actions.Callee{{Address: emitter}},
+	})
+	output = callOutput(t, result)
+	require.Equal(t, []actions.Event{{Contract: emitter, Topic: []byte("ping"), Data: []byte("second")}}, output.Events)
+
+	events, next, err := prep.instance.lcli.ContractEvents(context.Background(), emitterStr, []byte("ping"), 0, math.MaxUint64, nil)
+	require.NoError(t, err)
+	require.Nil(t, next)
+	require.Len(t, events, 2)
+	require.Equal(t, []byte("first"), events[0].Data)
+	require.Equal(t, []byte("second"), events[1].Data)
+	firstHeight := events[0].Height
+	require.Less(t, firstHeight, events[1].Height)
+
+	events, _, err = prep.instance.lcli.ContractEvents(context.Background(), emitterStr, []byte("other"), 0, math.MaxUint64, nil)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	events, _, err = prep.instance.lcli.ContractEvents(context.Background(), emitterStr, nil, firstHeight+1, math.MaxUint64, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []byte("second"), events[0].Data)
+}
+
+func TestContractEventsOfFailedCallee(t *testing.T) {
+	failingBytecode, err := wasmtime.Wat2Wasm(failingEmitterContractWat)
+	require.NoError(t, err)
+	floodBytecode, err := wasmtime.Wat2Wasm(floodContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	failing := prep.deployContract(t, failingBytecode)
+	flood := prep.deployContract(t, floodBytecode)
+
+	//the discarded event of the callee doesn't count against the limit
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: flood,
+		Payload:         failing[:],
+		MaxFuel:         callMaxFuel,
+		Callees:         []actions.Callee{{Address: failing}},
+	})
+	output := callOutput(t, result)
+	require.Len(t, output.Events, actions.CallContractMaxEvents)
+	for _, event := range output.Events {
+		require.Equal(t, flood, event.Contract)
+	}
+}
+
+func TestContractEventsPages(t *testing.T) {
+	failingBytecode, err := wasmtime.Wat2Wasm(failingEmitterContractWat)
+	require.NoError(t, err)
+	floodBytecode, err := wasmtime.Wat2Wasm(floodContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	failing := prep.deployContract(t, failingBytecode)
+	flood := prep.deployContract(t, floodBytecode)
+	floodStr := codec.MustAddressBech32(lconsts.HRP, flood)
+
+	//more events than fit in a page, each call emits CallContractMaxEvents
+	calls := 5
+	for i := 0; i < calls; i++ {
+		result := prep.sendAction(t, &actions.CallContract{
+			ContractAddress: flood,
+			Payload:         append(failing[:], byte(i)),
+			MaxFuel:         callMaxFuel,
+			Callees:         []actions.Callee{{Address: failing}},
+		})
+		require.True(t, result.Success, string(result.Output))
+	}
+
+	events, next, err := prep.instance.lcli.ContractEvents(context.Background(), floodStr, []byte("ping"), 0, math.MaxUint64, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 1024)
+	require.NotNil(t, next)
+
+	//the next page starts at the cursor, with the last call
+	page, last, err := prep.instance.lcli.ContractEvents(context.Background(), floodStr, []byte("ping"), 0, math.MaxUint64, next)
+	require.NoError(t, err)
+	require.Nil(t, last)
+	require.Len(t, page, calls*actions.CallContractMaxEvents-1024)
+	require.Equal(t, next.Height, page[0].Height)
+	require.Less(t, events[1023].Height, page[0].Height)
+	require.Equal(t, page[0].Height, page[len(page)-1].Height)
+}
@@ -0,0 +1,68 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/stretchr/testify/require"
+)
+
+// splitterContractWat sends half of its balance to the address in its input
+// and returns the value attached to the call
+const splitterContractWat = `
+(module
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "value" (func $value (result i64)))
+  (import "polyglot_v1" "balance" (func $balance (result i64)))
+  (import "polyglot_v1" "transfer" (func $transfer (param i32 i64)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (call $input_read (i32.const 0))
+    (call $transfer (i32.const 0) (i64.div_u (call $balance) (i64.const 2)))
+    (i64.store (i32.const 64) (call $value))
+    (call $set_result (i32.const 64) (i32.const 8))))
+`
+
+func TestContractFunds(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(splitterContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Value:           1000,
+		Recipients:      []codec.Address{prep.addr2},
+	})
+	require.Equal(t, uint64(1000), binary.LittleEndian.Uint64(callOutput(t, result).Result))
+
+	contractBalance, err := prep.instance.lcli.Balance(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(500), contractBalance)
+	recipientBalance, err := prep.instance.lcli.Balance(context.Background(), prep.addrStr2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(500), recipientBalance)
+
+	//the recipient must be declared, the value is returned on failure
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         prep.addr2[:],
+		MaxFuel:         callMaxFuel,
+		Value:           1000,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrRecipientNotDeclared.Error())
+
+	contractBalance, err = prep.instance.lcli.Balance(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(500), contractBalance)
+}
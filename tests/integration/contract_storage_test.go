@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1wasm"
+	"github.com/stretchr/testify/require"
+)
+
+// storageContractWat stores its input under "key" and returns the previous
+// value
+const storageContractWat = `
+(module
+  (import "polyglot_v1" "input_size" (func $input_size (result i32)))
+  (import "polyglot_v1" "input_read" (func $input_read (param i32)))
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "storage_read" (func $storage_read (param i32 i32 i32 i32) (result i32)))
+  (import "polyglot_v1" "storage_write" (func $storage_write (param i32 i32 i32 i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "key")
+  (func (export "_start")
+    (local $prev i32)
+    (call $input_read (i32.const 1024))
+    (local.set $prev (call $storage_read (i32.const 0) (i32.const 3) (i32.const 2048) (i32.const 1024)))
+    (if (i32.ge_s (local.get $prev) (i32.const 0))
+      (then (call $set_result (i32.const 2048) (local.get $prev))))
+    (call $storage_write (i32.const 0) (i32.const 3) (i32.const 1024) (call $input_size))))
+`
+
+func TestContractStorage(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+	storageKeys := [][]byte{[]byte("key")}
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("first"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.True(t, result.Success, string(result.Output))
+	require.Nil(t, result.Output)
+
+	value, err := prep.instance.lcli.ContractStorage(context.Background(), contractAddrStr, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), value)
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("second"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.Equal(t, []byte("first"), callOutput(t, result).Result)
+
+	//simulated calls see every key and change nothing
+	res, _, _, err := prep.instance.lcli.SimulateCall(context.Background(), contractAddrStr, "", []byte("third"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), res)
+
+	value, err = prep.instance.lcli.ContractStorage(context.Background(), contractAddrStr, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), value)
+}
+
+func TestContractStorageNotDeclared(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("first"),
+		MaxFuel:         callMaxFuel,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrStorageKeyNotDeclared.Error())
+}
+
+func TestContractStorageWasmEngine(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1wasm.EngineID,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddrStr := string(result.Output)
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, contractAddrStr)
+	require.NoError(t, err)
+
+	info, err := prep.instance.lcli.ContractInfo(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, v1wasm.EngineID, info.EngineID)
+
+	for _, payload := range []string{"first", "second"} {
+		result = prep.sendAction(t, &actions.CallContract{
+			ContractAddress: contractAddr,
+			Payload:         []byte(payload),
+			MaxFuel:         callMaxFuel,
+			StorageKeys:     [][]byte{[]byte("key")},
+		})
+		require.True(t, result.Success, string(result.Output))
+	}
+	require.Equal(t, []byte("first"), callOutput(t, result).Result)
+}
@@ -0,0 +1,182 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1wasm"
+	"github.com/stretchr/testify/require"
+)
+
+// readerContractWat returns the value stored under "key"
+const readerContractWat = `
+(module
+  (import "polyglot_v1" "set_result" (func $set_result (param i32 i32)))
+  (import "polyglot_v1" "storage_read" (func $storage_read (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "key")
+  (func (export "_start")
+    (call $set_result (i32.const 1024)
+      (call $storage_read (i32.const 0) (i32.const 3) (i32.const 1024) (i32.const 1024)))))
+`
+
+func TestUpgradeContract(t *testing.T) {
+	storageBytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	readerBytecode, err := wasmtime.Wat2Wasm(readerContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	for _, to := range []codec.Address{prep.addr2, prep.addr3} {
+		result := prep.sendAction(t, &actions.Transfer{To: to, Value: 1_000_000})
+		require.True(t, result.Success, string(result.Output))
+	}
+
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      storageBytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1javy.EngineID,
+		Admin:         prep.addr2,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddrStr := string(result.Output)
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, contractAddrStr)
+	require.NoError(t, err)
+	storageKeys := [][]byte{[]byte("key")}
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("kept"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	//neither the creator nor the admin
+	result = prep.sendActionAs(t, prep.factory3, &actions.UpgradeContract{
+		ContractAddress: contractAddr,
+		Bytecode:        readerBytecode,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrNotContractAdmin.Error())
+
+	result = prep.sendActionAs(t, prep.factory2, &actions.UpgradeContract{
+		ContractAddress: contractAddr,
+		Bytecode:        readerBytecode,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	bytecode, err := prep.instance.lcli.ContractBytecode(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, readerBytecode, bytecode)
+	info, err := prep.instance.lcli.ContractInfo(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, utils.ToID(readerBytecode), info.CodeHash)
+	require.Equal(t, prep.addrStr, info.Creator)
+	require.Equal(t, prep.addrStr2, info.Admin)
+
+	//the storage survives the upgrade
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.Equal(t, []byte("kept"), callOutput(t, result).Result)
+
+	//the creator can upgrade too
+	result = prep.sendAction(t, &actions.UpgradeContract{
+		ContractAddress: contractAddr,
+		Bytecode:        storageBytecode,
+	})
+	require.True(t, result.Success, string(result.Output))
+}
+
+func TestUpgradeContractFromCode(t *testing.T) {
+	storageBytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	readerBytecode, err := wasmtime.Wat2Wasm(readerContractWat)
+	require.NoError(t, err)
+	codeHash := utils.ToID(storageBytecode)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.UploadCode{EngineID: v1wasm.EngineID, Bytecode: storageBytecode})
+	require.True(t, result.Success, string(result.Output))
+	result = prep.sendAction(t, &actions.CreateContractFromCode{
+		CodeHash:      codeHash,
+		InitialState:  []byte{},
+		Discriminator: 1,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddrStr := string(result.Output)
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, contractAddrStr)
+	require.NoError(t, err)
+	storageKeys := [][]byte{[]byte("key")}
+
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("kept"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+		Codes:           []ids.ID{codeHash},
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	//the contract gets its own bytecode
+	result = prep.sendAction(t, &actions.UpgradeContract{
+		ContractAddress: contractAddr,
+		Bytecode:        readerBytecode,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	bytecode, err := prep.instance.lcli.ContractBytecode(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, readerBytecode, bytecode)
+	info, err := prep.instance.lcli.ContractInfo(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, utils.ToID(readerBytecode), info.CodeHash)
+	require.Equal(t, v1wasm.EngineID, info.EngineID)
+
+	//and no longer needs the code
+	result = prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     storageKeys,
+	})
+	require.Equal(t, []byte("kept"), callOutput(t, result).Result)
+}
+
+func TestUpgradeImmutableContract(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1javy.EngineID,
+		Immutable:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+	require.NoError(t, err)
+
+	info, err := prep.instance.lcli.ContractInfo(context.Background(), string(result.Output))
+	require.NoError(t, err)
+	require.True(t, info.Immutable)
+	require.Empty(t, info.Admin)
+
+	result = prep.sendAction(t, &actions.UpgradeContract{
+		ContractAddress: contractAddr,
+		Bytecode:        []byte("new"),
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), actions.ErrContractImmutable.Error())
+}
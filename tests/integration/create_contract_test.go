@@ -4,12 +4,21 @@ import (
 	"context"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/bytecodealliance/wasmtime-go/v19"
 	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	"github.com/containerman17/avalanche-polyglot-subnet/auth"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/containerman17/avalanche-polyglot-subnet/genesis"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCreateContract(t *testing.T) {
-	dummyBytecode := []byte{0x01, 0x02, 0x03}
+	dummyBytecode, err := wasmtime.Wat2Wasm(`(module (func (export "_start")))`)
+	require.NoError(t, err)
 	dummyState := []byte{0x04, 0x05, 0x06}
 	discriminator := 123
 
@@ -27,6 +36,7 @@ func TestCreateContract(t *testing.T) {
 			Bytecode:      dummyBytecode,
 			InitialState:  dummyState,
 			Discriminator: uint16(discriminator),
+			EngineID:      v1javy.EngineID,
 		},
 		prep.factory,
 	)
@@ -42,10 +52,80 @@ func TestCreateContract(t *testing.T) {
 	//check bytecode and state
 	bytecodeFromChain, err := prep.instance.lcli.ContractBytecode(context.Background(), contractAddrString)
 	require.NoError(t, err)
-	require.Equal(t, []byte{0x01, 0x02, 0x03}, bytecodeFromChain)
+	require.Equal(t, dummyBytecode, bytecodeFromChain)
 
 	stateFromChain, err := prep.instance.lcli.ContractState(context.Background(), contractAddrString)
 	require.NoError(t, err)
 	require.Equal(t, []byte{0x04, 0x05, 0x06}, stateFromChain)
 
+	info, err := prep.instance.lcli.ContractInfo(context.Background(), contractAddrString)
+	require.NoError(t, err)
+	require.Equal(t, prep.addrStr, info.Creator)
+	require.Equal(t, utils.ToID(dummyBytecode), info.CodeHash)
+	require.Positive(t, info.CreatedAt)
+	require.Equal(t, v1javy.EngineID, info.EngineID)
+}
+
+func TestCreateContractComputeUnits(t *testing.T) {
+	bytecode := loadCountersWasm(t)
+	prep := prepare(t)
+
+	//compiling the bytecode is priced by its size
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1javy.EngineID,
+	})
+	require.True(t, result.Success, string(result.Output))
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	units := actions.BytecodeComputeUnits(rules, bytecode)
+	require.Greater(t, units, uint64(len(bytecode))/rules.GetContractBytecodeBytesPerComputeUnit())
+	require.Equal(t, rules.GetBaseComputeUnits()+auth.ED25519ComputeUnits+units, result.Consumed[fees.Compute])
+}
+
+func TestCreateContractInvalidBytecode(t *testing.T) {
+	prep := prepare(t)
+	wat := func(src string) []byte {
+		bytecode, err := wasmtime.Wat2Wasm(src)
+		require.NoError(t, err)
+		return bytecode
+	}
+	tooLarge := wat(`(module (func (export "_start")) (data (i32.const 0) "x"))`)
+	tooLarge = append(tooLarge, make([]byte, genesis.Default().ContractMaxBytecodeSize)...)
+
+	for i, test := range []struct {
+		bytecode []byte
+		err      error
+	}{
+		{[]byte{0x01, 0x02, 0x03}, execution.ErrInvalidModule},
+		{wat(`(module (import "env" "f" (func)) (func (export "_start")))`), execution.ErrImportNotAllowed},
+		{wat(`(module (import "polyglot_v1" "input_size" (func (param i32))) (func (export "_start")))`), execution.ErrImportMismatch},
+		{wat(`(module (func (export "main")))`), execution.ErrStartNotExported},
+		{tooLarge, actions.ErrBytecodeTooLarge},
+	} {
+		result := prep.sendAction(t, &actions.CreateContract{
+			Bytecode:      test.bytecode,
+			InitialState:  []byte{},
+			Discriminator: uint16(i),
+			EngineID:      v1javy.EngineID,
+		})
+		require.False(t, result.Success)
+		require.Contains(t, string(result.Output), test.err.Error())
+	}
+}
+
+func TestCreateContractUnknownEngine(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(`(module (func (export "_start")))`)
+	require.NoError(t, err)
+	prep := prepare(t)
+
+	result := prep.sendAction(t, &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      0xff,
+	})
+	require.False(t, result.Success)
+	require.Contains(t, string(result.Output), execution.ErrUnknownEngine.Error())
 }
@@ -0,0 +1,68 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/execution/v1javy"
+	"github.com/stretchr/testify/require"
+)
+
+// requireCovers checks that [estimated] covers [consumed] in every dimension.
+func requireCovers(t *testing.T, estimated fees.Dimensions, consumed fees.Dimensions) {
+	for i := range estimated {
+		require.GreaterOrEqual(t, estimated[i], consumed[i], "dimension %d", i)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	bytecode, err := wasmtime.Wat2Wasm(storageContractWat)
+	require.NoError(t, err)
+	prep := prepare(t)
+	ctx := context.Background()
+
+	create := &actions.CreateContract{
+		Bytecode:      bytecode,
+		InitialState:  []byte{},
+		Discriminator: 1,
+		EngineID:      v1javy.EngineID,
+	}
+	estimate, err := prep.instance.lcli.Estimate(ctx, prep.addrStr, create)
+	require.NoError(t, err)
+	require.Zero(t, estimate.FuelConsumed)
+	result := prep.sendAction(t, create)
+	require.True(t, result.Success, string(result.Output))
+	requireCovers(t, estimate.Units, result.Consumed)
+	require.Equal(t, estimate.Units[fees.Bandwidth], result.Consumed[fees.Bandwidth])
+	require.GreaterOrEqual(t, estimate.Fee, result.Fee)
+	contractAddr, err := codec.ParseAddressBech32(lconsts.HRP, string(result.Output))
+	require.NoError(t, err)
+
+	call := &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         []byte("value"),
+		MaxFuel:         callMaxFuel,
+		StorageKeys:     [][]byte{[]byte("key")},
+	}
+	estimate, err = prep.instance.lcli.Estimate(ctx, prep.addrStr, call)
+	require.NoError(t, err)
+	require.NotZero(t, estimate.FuelConsumed)
+
+	//a call sent with the fuel consumed by the dry run consumes its compute
+	call.MaxFuel = estimate.FuelConsumed
+	result = prep.sendAction(t, call)
+	require.True(t, result.Success, string(result.Output))
+	requireCovers(t, estimate.Units, result.Consumed)
+	require.Equal(t, estimate.Units[fees.Compute], result.Consumed[fees.Compute])
+	require.GreaterOrEqual(t, estimate.Fee, result.Fee)
+
+	//the dry run only accesses the declared keys
+	call.StorageKeys = nil
+	_, err = prep.instance.lcli.Estimate(ctx, prep.addrStr, call)
+	require.ErrorContains(t, err, actions.ErrStorageKeyNotDeclared.Error())
+}
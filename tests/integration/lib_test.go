@@ -123,6 +123,8 @@ type prepeareResult struct {
 	addrStr3 string
 
 	blocks []snowman.Block
+
+	deployed uint16 // contracts deployed by deployContract
 }
 
 func prepare(t *testing.T) prepeareResult {
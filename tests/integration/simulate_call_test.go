@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/bytecodealliance/wasmtime-go/v19"
+	"github.com/containerman17/avalanche-polyglot-subnet/actions"
+	lconsts "github.com/containerman17/avalanche-polyglot-subnet/consts"
+	"github.com/containerman17/avalanche-polyglot-subnet/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateCall(t *testing.T) {
+	bytecode := loadCountersWasm(t)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+	contractAddrStr := codec.MustAddressBech32(lconsts.HRP, contractAddr)
+
+	result := prep.sendAction(t, &actions.CallContract{
+		ContractAddress: contractAddr,
+		Payload:         binary.LittleEndian.AppendUint64([]byte{0x0}, 42),
+		MaxFuel:         callMaxFuel,
+		WritesState:     true,
+	})
+	require.True(t, result.Success, string(result.Output))
+
+	stateBefore, err := prep.instance.lcli.ContractState(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+
+	//view call
+	res, _, fuel, err := prep.instance.lcli.SimulateCall(
+		context.Background(),
+		contractAddrStr,
+		"",
+		append([]byte{0x2}, prep.addr[:]...),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), binary.LittleEndian.Uint64(res))
+	require.Positive(t, fuel)
+
+	//state changes are discarded
+	_, _, _, err = prep.instance.lcli.SimulateCall(
+		context.Background(),
+		contractAddrStr,
+		prep.addrStr,
+		binary.LittleEndian.AppendUint64([]byte{0x0}, 1),
+	)
+	require.NoError(t, err)
+
+	stateAfter, err := prep.instance.lcli.ContractState(context.Background(), contractAddrStr)
+	require.NoError(t, err)
+	require.Equal(t, stateBefore, stateAfter)
+}
+
+func TestSimulateCallNotFound(t *testing.T) {
+	prep := prepare(t)
+
+	_, _, _, err := prep.instance.lcli.SimulateCall(
+		context.Background(),
+		codec.MustAddressBech32(lconsts.HRP, storage.GenerateContractAddress(prep.addr, 42)),
+		"",
+		[]byte{0x2},
+	)
+	require.ErrorContains(t, err, storage.ErrContractNotFound.Error())
+}
+
+func TestSimulateCallFailure(t *testing.T) {
+	//prints to stderr then traps
+	bytecode, err := wasmtime.Wat2Wasm(`
+(module
+  (import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 64) "oops")
+  (func (export "_start")
+    (i32.store (i32.const 0) (i32.const 64))
+    (i32.store (i32.const 4) (i32.const 4))
+    (drop (call $fd_write (i32.const 2) (i32.const 0) (i32.const 1) (i32.const 8)))
+    unreachable))
+`)
+	require.NoError(t, err)
+	prep := prepare(t)
+	contractAddr := prep.deployContract(t, bytecode)
+
+	//the console output and fuel of a failed call are returned with its error
+	res, stdErr, fuel, err := prep.instance.lcli.SimulateCall(
+		context.Background(),
+		codec.MustAddressBech32(lconsts.HRP, contractAddr),
+		"",
+		nil,
+	)
+	require.ErrorContains(t, err, "unreachable")
+	require.Nil(t, res)
+	require.Equal(t, []byte("oops"), stdErr)
+	require.Positive(t, fuel)
+}